@@ -0,0 +1,10 @@
+data "influxdb_continuous_queries" "telegraf" {
+  database = "telegraf"
+}
+
+output "telegraf_continuous_queries" {
+  value = {
+    for cq in flatten(data.influxdb_continuous_queries.telegraf.databases[*].continuous_queries) :
+    cq.name => cq.into
+  }
+}
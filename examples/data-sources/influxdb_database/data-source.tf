@@ -0,0 +1,9 @@
+data "influxdb_database" "metrics" {
+  name = "metrics"
+}
+
+resource "influxdb_continuous_query" "hourly" {
+  name     = "hourly"
+  database = data.influxdb_database.metrics.name
+  query    = "SELECT mean(value) INTO \"${data.influxdb_database.metrics.default_retention_policy}\".\"hourly\" FROM \"cpu\" GROUP BY time(1h)"
+}
@@ -0,0 +1,9 @@
+data "influxdb_databases" "telegraf" {
+  include                 = "^telegraf_"
+  exclude                 = "_test$"
+  with_retention_policies = true
+}
+
+output "telegraf_databases" {
+  value = data.influxdb_databases.telegraf.names
+}
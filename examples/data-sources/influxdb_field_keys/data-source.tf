@@ -0,0 +1,15 @@
+data "influxdb_field_keys" "cpu" {
+  database    = "telegraf"
+  measurement = "^cpu$"
+}
+
+resource "influxdb_continuous_query" "cpu_hourly" {
+  for_each = toset([
+    for field in data.influxdb_field_keys.cpu.measurements[0].fields :
+    field.name if field.type == "float"
+  ])
+
+  name     = "cpu_${each.key}_hourly"
+  database = "telegraf"
+  query    = "SELECT mean(\"${each.key}\") AS \"${each.key}\" INTO \"cpu_hourly\" FROM \"cpu\" GROUP BY time(1h), *"
+}
@@ -0,0 +1,4 @@
+data "influxdb_measurements" "cpu" {
+  database    = "telegraf"
+  measurement = "^cpu"
+}
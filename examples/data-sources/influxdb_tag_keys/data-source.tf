@@ -0,0 +1,11 @@
+data "influxdb_tag_keys" "telegraf" {
+  database         = "telegraf"
+  retention_policy = "autogen"
+}
+
+locals {
+  tag_keys = {
+    for measurement in data.influxdb_tag_keys.telegraf.measurements :
+    measurement.name => measurement.tag_keys
+  }
+}
@@ -0,0 +1,9 @@
+data "influxdb_tag_values" "hosts" {
+  database    = "telegraf"
+  measurement = "^cpu$"
+  key         = "host"
+}
+
+output "hosts" {
+  value = data.influxdb_tag_values.hosts.values
+}
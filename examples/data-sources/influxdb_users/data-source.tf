@@ -0,0 +1,10 @@
+data "influxdb_users" "all" {
+  exclude = "^ops_"
+}
+
+check "no_unexpected_admins" {
+  assert {
+    condition     = length([for user in data.influxdb_users.all.users : user.name if user.admin]) == 0
+    error_message = "Only ops_ users may be admins."
+  }
+}
@@ -0,0 +1,9 @@
+resource "influxdb_database" "example" {
+  name = "example"
+}
+
+resource "influxdb_grant" "reader" {
+  user      = "reader"
+  database  = influxdb_database.example.name
+  privilege = "READ"
+}
@@ -0,0 +1,14 @@
+resource "influxdb_database" "example" {
+  name = "example"
+}
+
+resource "influxdb_points" "regions" {
+  database  = influxdb_database.example.name
+  precision = "s"
+  body      = <<-EOT
+    region,code=eu name="Europe" 1700000000
+    region,code=us name="America" 1700000000
+  EOT
+
+  delete_on_destroy = true
+}
@@ -0,0 +1,11 @@
+resource "influxdb_database" "example" {
+  name = "example"
+}
+
+resource "influxdb_retention_policy" "example" {
+  database       = influxdb_database.example.name
+  name           = "2days"
+  duration       = "2d"
+  shard_duration = "1h"
+  default        = true
+}
@@ -0,0 +1,4 @@
+resource "influxdb_series_cleanup" "bad_host" {
+  database  = "telegraf"
+  statement = "DROP SERIES FROM \"cpu\" WHERE \"host\" = 'bad-host'"
+}
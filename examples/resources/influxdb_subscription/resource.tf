@@ -0,0 +1,11 @@
+resource "influxdb_database" "example" {
+  name = "example"
+}
+
+resource "influxdb_subscription" "kapacitor" {
+  name             = "kapacitor"
+  database         = influxdb_database.example.name
+  retention_policy = "autogen"
+  mode             = "ANY"
+  destinations     = ["http://kapacitor:9092"]
+}
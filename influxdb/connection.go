@@ -0,0 +1,767 @@
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/influxdata/influxdb/client"
+)
+
+const (
+	// endpointsFailover sends every statement to the first healthy endpoint.
+	endpointsFailover = "failover"
+	// endpointsBroadcast sends mutating statements to every endpoint and
+	// reads from the first one.
+	endpointsBroadcast = "broadcast"
+)
+
+// connectionConfig holds the connection settings read from the provider block.
+type connectionConfig struct {
+	Endpoints       []string
+	EndpointsMode   string
+	Username        string
+	Password        string
+	SharedSecret    string
+	TokenLifetime   time.Duration
+	TLS             *tls.Config
+	ProxyURL        *url.URL
+	Headers         map[string]string
+	UserAgent       string
+	MaxRetries      int
+	RetryMinBackoff time.Duration
+	RetryMaxBackoff time.Duration
+	WaitForReady    time.Duration
+	DryRun          bool
+	StatementLog    string
+}
+
+// connection is the provider meta shared by every resource. It speaks the
+// InfluxDB 1.x HTTP API and retries requests failing with a transient error.
+type connection struct {
+	config    connectionConfig
+	endpoints []*endpoint
+
+	// mu guards the active failover endpoint and the statement log.
+	mu     sync.Mutex
+	active int
+}
+
+// endpoint is one of the InfluxDB servers of a connection.
+type endpoint struct {
+	name       string
+	url        url.URL
+	httpClient *http.Client
+
+	// mu guards ready and wait, see waitForReady.
+	mu    sync.Mutex
+	ready bool
+	wait  *readiness
+}
+
+// readiness is a wait for an endpoint to be ready, shared by the statements
+// sent to the endpoint while it is in progress.
+type readiness struct {
+	// done is closed once the wait ended, with err set when the endpoint
+	// is still not ready.
+	done chan struct{}
+	err  error
+}
+
+// statusError is returned when the server answers with an unexpected HTTP status.
+type statusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *statusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("received status code %d from server", e.StatusCode)
+	}
+	return fmt.Sprintf("received status code %d from server: %s", e.StatusCode, e.Message)
+}
+
+// transientError marks a failure which may succeed when retried: connection
+// errors and 5xx responses. InfluxQL and authorization errors are never transient.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+func newConnection(config connectionConfig) (*connection, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("no InfluxDB URL")
+	}
+
+	conn := &connection{
+		config: config,
+	}
+
+	for _, rawURL := range config.Endpoints {
+		e, err := newEndpoint(rawURL, config)
+		if err != nil {
+			return nil, err
+		}
+		conn.endpoints = append(conn.endpoints, e)
+	}
+
+	return conn, nil
+}
+
+func newEndpoint(rawURL string, config connectionConfig) (*endpoint, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid InfluxDB URL: %w", err)
+	}
+
+	name := u.Redacted()
+
+	transport := &http.Transport{
+		TLSClientConfig: config.TLS,
+	}
+
+	if config.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(config.ProxyURL)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	case "unix":
+		// unix:///var/run/influxdb.sock, requests are sent to a placeholder
+		// host through the socket.
+		socket := u.Path
+		if socket == "" {
+			return nil, fmt.Errorf("invalid InfluxDB URL: missing socket path in %s", rawURL)
+		}
+
+		transport.DisableCompression = true
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		u = &url.URL{Scheme: "http", Host: "localhost"}
+	default:
+		return nil, fmt.Errorf("invalid InfluxDB URL: unsupported scheme %q, expected http, https or unix", u.Scheme)
+	}
+
+	return &endpoint{
+		name:       name,
+		url:        *u,
+		httpClient: &http.Client{Transport: transport},
+	}, nil
+}
+
+// tlsOptions holds the TLS settings of the provider block. Certificates and
+// keys are either PEM content or paths to PEM files.
+type tlsOptions struct {
+	SkipVerify        bool
+	CACertificate     string
+	ClientCertificate string
+	ClientKey         string
+	ServerName        string
+}
+
+func newTLSConfig(options tlsOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: options.SkipVerify,
+		ServerName:         options.ServerName,
+	}
+
+	if options.CACertificate != "" {
+		ca, err := readPEM(options.CACertificate)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_certificate: %w", err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("error reading ca_certificate: no PEM encoded certificate found")
+		}
+	}
+
+	if options.ClientCertificate != "" || options.ClientKey != "" {
+		if options.ClientCertificate == "" || options.ClientKey == "" {
+			return nil, fmt.Errorf("client_certificate and client_key must be set together")
+		}
+
+		certificate, err := readPEM(options.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client_certificate: %w", err)
+		}
+		key, err := readPEM(options.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client_key: %w", err)
+		}
+
+		pair, err := tls.X509KeyPair(certificate, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return config, nil
+}
+
+// readPEM returns value when it holds PEM content, otherwise the content of
+// the file it names.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// QueryContext sends a read-only command to the server and returns its
+// response, retrying transient failures with an exponential backoff.
+func (c *connection) QueryContext(ctx context.Context, q client.Query) (*client.Response, error) {
+	var resp *client.Response
+
+	run := func(e *endpoint) error {
+		var err error
+		resp, err = c.query(ctx, e, q, false)
+		return err
+	}
+
+	if c.config.EndpointsMode == endpointsBroadcast {
+		err := c.onEndpoint(ctx, c.endpoints[0], run)
+		return resp, c.endpointError(c.endpoints[0], err)
+	}
+
+	err := c.failover(ctx, run)
+	return resp, err
+}
+
+// ExecContext sends a mutating command to the server. In broadcast mode, the
+// command is sent to every endpoint. In dry run mode, the command is only
+// recorded.
+func (c *connection) ExecContext(ctx context.Context, q client.Query) (*client.Response, error) {
+	if err := c.logStatement(ctx, q.Command); err != nil {
+		return nil, err
+	}
+
+	if c.config.DryRun {
+		return &client.Response{}, nil
+	}
+
+	if c.config.EndpointsMode != endpointsBroadcast {
+		var resp *client.Response
+		err := c.failover(ctx, func(e *endpoint) error {
+			var err error
+			resp, err = c.query(ctx, e, q, true)
+			return err
+		})
+		return resp, err
+	}
+
+	var first *client.Response
+	err := c.broadcast(ctx, func(e *endpoint) error {
+		resp, err := c.query(ctx, e, q, true)
+		if err == nil && resp.Err != nil {
+			return resp.Err
+		}
+		if err == nil && first == nil {
+			first = resp
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return first, nil
+}
+
+// WriteContext writes a line protocol body to a database, to every endpoint
+// in broadcast mode. In dry run mode, the write is only recorded.
+func (c *connection) WriteContext(ctx context.Context, w pointsWrite) error {
+	if err := c.logStatement(ctx, fmt.Sprintf("-- write %d bytes to %q.%q with precision %s", len(w.Body), w.Database, w.RetentionPolicy, w.Precision)); err != nil {
+		return err
+	}
+
+	if c.config.DryRun {
+		return nil
+	}
+
+	run := func(e *endpoint) error {
+		return c.write(ctx, e, w)
+	}
+
+	if c.config.EndpointsMode == endpointsBroadcast {
+		return c.broadcast(ctx, run)
+	}
+	return c.failover(ctx, run)
+}
+
+// logStatement records a mutating statement, with passwords redacted, in the
+// statement log file.
+func (c *connection) logStatement(ctx context.Context, command string) error {
+	statement := redactStatement(command)
+
+	tflog.Info(ctx, "InfluxQL statement", map[string]interface{}{
+		"statement": statement,
+		"dry_run":   c.config.DryRun,
+	})
+
+	if c.config.StatementLog == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := os.OpenFile(c.config.StatementLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening statement log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s;\n", statement); err != nil {
+		return fmt.Errorf("error writing statement log: %w", err)
+	}
+
+	return nil
+}
+
+// pointsWrite is a line protocol body written to a retention policy of a
+// database, with timestamps in the given precision.
+type pointsWrite struct {
+	Database        string
+	RetentionPolicy string
+	Precision       string
+	Body            []byte
+}
+
+// failover runs f on the active endpoint, moving to the next endpoints
+// while the failure is transient.
+func (c *connection) failover(ctx context.Context, f func(e *endpoint) error) error {
+	c.mu.Lock()
+	active := c.active
+	c.mu.Unlock()
+
+	var errs []error
+	for i := range c.endpoints {
+		index := (active + i) % len(c.endpoints)
+		e := c.endpoints[index]
+
+		err := c.onEndpoint(ctx, e, f)
+
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || ctx.Err() != nil {
+			if err == nil && index != active {
+				tflog.Warn(ctx, "InfluxDB endpoint failed over", map[string]interface{}{
+					"endpoint": e.name,
+				})
+				c.mu.Lock()
+				c.active = index
+				c.mu.Unlock()
+			}
+			return c.endpointError(e, err)
+		}
+
+		errs = append(errs, c.endpointError(e, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// broadcast runs f on every endpoint and joins their errors. When f failed
+// on some endpoints only, the error names the endpoints where it succeeded, as
+// they have to be reconciled.
+func (c *connection) broadcast(ctx context.Context, f func(e *endpoint) error) error {
+	var errs []error
+	var applied []string
+	for _, e := range c.endpoints {
+		if err := c.onEndpoint(ctx, e, f); err != nil {
+			errs = append(errs, c.endpointError(e, err))
+		} else {
+			applied = append(applied, e.name)
+		}
+	}
+
+	if len(errs) > 0 && len(applied) > 0 {
+		errs = append(errs, fmt.Errorf("already applied on %s", strings.Join(applied, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// onEndpoint runs f on e once it is ready, retrying transient failures.
+func (c *connection) onEndpoint(ctx context.Context, e *endpoint, f func(e *endpoint) error) error {
+	if err := c.waitForReady(ctx, e); err != nil {
+		// a server which is not ready may be failed over.
+		return &transientError{err: err}
+	}
+
+	return c.retry(ctx, func() error {
+		return f(e)
+	})
+}
+
+// endpointError names the endpoint which failed, when there are several.
+func (c *connection) endpointError(e *endpoint, err error) error {
+	if err == nil || len(c.endpoints) == 1 {
+		return err
+	}
+	return fmt.Errorf("endpoint %s: %w", e.name, err)
+}
+
+// waitForReady checks the endpoint is an InfluxDB server before its first
+// statement, waiting for it to answer for at most the wait_for_ready duration.
+// An endpoint is only checked by a single wait at a time, which every
+// statement sent to it meanwhile waits for, until its own context is done.
+func (c *connection) waitForReady(ctx context.Context, e *endpoint) error {
+	e.mu.Lock()
+	if e.ready {
+		e.mu.Unlock()
+		return nil
+	}
+	wait := e.wait
+	if wait == nil {
+		wait = &readiness{done: make(chan struct{})}
+		e.wait = wait
+		// the wait outlives the statement starting it, the others wait for it too.
+		go c.pollReady(context.WithoutCancel(ctx), e, wait)
+	}
+	e.mu.Unlock()
+
+	select {
+	case <-wait.done:
+		return wait.err
+	case <-ctx.Done():
+		return fmt.Errorf("error connecting server: %w", ctx.Err())
+	}
+}
+
+// pollReady checks the endpoint, polling it while wait_for_ready is set, then
+// ends wait. A failed wait is not kept: the next statement starts a new one.
+func (c *connection) pollReady(ctx context.Context, e *endpoint, wait *readiness) {
+	var err error
+	if c.config.WaitForReady == 0 {
+		err = c.retry(ctx, func() error {
+			return c.checkVersion(ctx, e)
+		})
+	} else {
+		err = c.pollHealth(ctx, e)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("error connecting server: %w", err)
+	}
+
+	e.mu.Lock()
+	e.ready = err == nil
+	e.wait = nil
+	wait.err = err
+	e.mu.Unlock()
+
+	close(wait.done)
+}
+
+// pollHealth polls the endpoint until it is ready or wait_for_ready expires.
+func (c *connection) pollHealth(ctx context.Context, e *endpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.WaitForReady)
+	defer cancel()
+
+	for {
+		err := c.retry(ctx, func() error {
+			return c.checkReady(ctx, e)
+		})
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		tflog.Debug(ctx, "waiting for InfluxDB to be ready", map[string]interface{}{
+			"error": err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// checkVersion pings the server, which must report its InfluxDB version.
+func (c *connection) checkVersion(ctx context.Context, e *endpoint) error {
+	version, err := c.ping(ctx, e)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return fmt.Errorf("no version information, %s is not an InfluxDB server", e.name)
+	}
+	return nil
+}
+
+// checkReady checks the server version, then its /health endpoint which is
+// only available since InfluxDB 1.8.
+func (c *connection) checkReady(ctx context.Context, e *endpoint) error {
+	if err := c.checkVersion(ctx, e); err != nil {
+		return err
+	}
+
+	u := e.url
+	u.Path = path.Join(u.Path, "health")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(e, req, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// any answer but a server error means the server is up, such as 404
+	// before InfluxDB 1.8 or 401 from an authenticating proxy.
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &transientError{err: &statusError{StatusCode: resp.StatusCode}}
+	}
+
+	return nil
+}
+
+func (c *connection) retry(ctx context.Context, f func() error) error {
+	backoff := c.config.RetryMinBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := f()
+
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || attempt >= c.config.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		tflog.Warn(ctx, "transient error from InfluxDB, retrying", map[string]interface{}{
+			"error":   err.Error(),
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > c.config.RetryMaxBackoff {
+			backoff = c.config.RetryMaxBackoff
+		}
+	}
+}
+
+// query sends q to e. A mutating statement is not retried once it was sent,
+// as the server may have applied it before the connection failed.
+func (c *connection) query(ctx context.Context, e *endpoint, q client.Query, mutating bool) (*client.Response, error) {
+	u := e.url
+	u.Path = path.Join(u.Path, "query")
+
+	values := u.Query()
+	values.Set("q", q.Command)
+	values.Set("db", q.Database)
+	if q.RetentionPolicy != "" {
+		values.Set("rp", q.RetentionPolicy)
+	}
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(e, req, mutating)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response client.Response
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&response); err != nil {
+		// Ignore EOF errors if we got an invalid status code.
+		if !(err == io.EOF && resp.StatusCode != http.StatusOK) {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := &statusError{StatusCode: resp.StatusCode}
+		if response.Err != nil {
+			err.Message = response.Err.Error()
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &transientError{err: err}
+		}
+		if response.Err != nil {
+			return &response, nil
+		}
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *connection) write(ctx context.Context, e *endpoint, w pointsWrite) error {
+	u := e.url
+	u.Path = path.Join(u.Path, "write")
+
+	values := u.Query()
+	values.Set("db", w.Database)
+	if w.RetentionPolicy != "" {
+		values.Set("rp", w.RetentionPolicy)
+	}
+	if w.Precision != "" {
+		values.Set("precision", w.Precision)
+	}
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(w.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := c.do(e, req, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var response struct {
+		Err string `json:"error"`
+	}
+	// the error message is optional, the status code is enough
+	_ = json.NewDecoder(resp.Body).Decode(&response)
+
+	err = &statusError{StatusCode: resp.StatusCode, Message: response.Err}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &transientError{err: err}
+	}
+	return err
+}
+
+func (c *connection) ping(ctx context.Context, e *endpoint) (string, error) {
+	u := e.url
+	u.Path = path.Join(u.Path, "ping")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(e, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", &transientError{err: &statusError{StatusCode: resp.StatusCode}}
+	}
+
+	return resp.Header.Get("X-Influxdb-Version"), nil
+}
+
+// do sends req to e with the provider credentials. Network errors raised
+// before a response is received are transient, unless the request context is
+// done or a mutating request was already sent.
+func (c *connection) do(e *endpoint, req *http.Request, mutating bool) (*http.Response, error) {
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	if c.config.SharedSecret != "" {
+		token, err := signToken(c.config.Username, c.config.SharedSecret, time.Now().Add(c.config.TokenLifetime))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	var sent atomic.Bool
+	if mutating {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			WroteRequest: func(info httptrace.WroteRequestInfo) {
+				sent.Store(info.Err == nil)
+			},
+		}))
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		if sent.Load() {
+			return nil, fmt.Errorf("statement sent but its result is unknown, it may have been applied: %w", err)
+		}
+		var opErr *net.OpError
+		if req.Context().Err() == nil && (errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+			return nil, &transientError{err: err}
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// signToken returns a JWT signed with HS256, as expected by an InfluxDB server
+// configured with a shared-secret.
+func signToken(username, sharedSecret string, expiresAt time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "HS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"username": username,
+		"exp":      expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, []byte(sharedSecret))
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
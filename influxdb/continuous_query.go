@@ -1,18 +1,44 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/influxdata/influxdb/client"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
 )
 
 func resourceContinuousQuery() *schema.Resource {
 	return &schema.Resource{
-		Create: createContinuousQuery,
-		Read:   readContinuousQuery,
-		Delete: deleteContinuousQuery,
+		CreateContext: createContinuousQuery,
+		ReadContext:   readContinuousQuery,
+		UpdateContext: updateContinuousQuery,
+		DeleteContext: deleteContinuousQuery,
+		CustomizeDiff: customizeContinuousQueryDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importContinuousQuery,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceContinuousQueryV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeContinuousQueryV0,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -28,127 +54,488 @@ func resourceContinuousQuery() *schema.Resource {
 			"query": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"resample": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "",
 			},
+			"definition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backfill": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"since": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateInfluxDuration,
+						},
+						"chunk": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "1d",
+							ValidateFunc: validateInfluxDuration,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func createContinuousQuery(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func createContinuousQuery(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 
 	name := d.Get("name").(string)
 	database := d.Get("database").(string)
 	resample := d.Get("resample").(string)
 	quer := d.Get("query").(string)
 
-	var queryStr string
-	if resample == "" {
-		queryStr = fmt.Sprintf("CREATE CONTINUOUS QUERY %q ON %q BEGIN %s END", name, database, quer)
-	} else {
-		queryStr = fmt.Sprintf("CREATE CONTINUOUS QUERY %q ON %q RESAMPLE %s BEGIN %s END", name, database, resample, quer)
+	if err := execCreateContinuousQuery(ctx, conn, name, database, resample, quer); err != nil {
+		return diag.FromErr(err)
 	}
 
-	query := client.Query{
-		Command: queryStr,
+	d.SetId(continuousQueryId(database, name))
+
+	// statements were only recorded, there is nothing to read back
+	if !conn.config.DryRun {
+		diags := readContinuousQuery(ctx, d, meta)
+		if diags.HasError() {
+			return diags
+		}
+		// check that cq is created
+		if d.Id() == "" {
+			return diag.Errorf("unable to create continuous query '%s', check your sql query", name)
+		}
 	}
 
-	resp, err := conn.Query(query)
+	if v, ok := d.GetOk("backfill"); ok {
+		backfill := v.([]interface{})[0].(map[string]interface{})
+		if err := backfillContinuousQuery(ctx, conn, name, database, quer, backfill["since"].(string), backfill["chunk"].(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// backfillContinuousQuery runs the SELECT body of a continuous query over the
+// last since duration, one chunk after the other, as the continuous query only
+// processes new intervals.
+func backfillContinuousQuery(ctx context.Context, conn *connection, name, database, quer, since, chunk string) error {
+	offset, err := backfillOffset(quer, chunk)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to backfill continuous query '%s': %w", name, err)
+	}
+
+	// durations are checked by validateInfluxDuration
+	sinceDuration, _ := influxql.ParseDuration(since)
+	chunkDuration, _ := influxql.ParseDuration(chunk)
+
+	end := time.Now().UTC()
+	start := backfillStart(end, sinceDuration, chunkDuration, offset)
+	chunks := int((end.Sub(start) + chunkDuration - 1) / chunkDuration)
+
+	for i := 0; start.Before(end); i++ {
+		chunkEnd := start.Add(chunkDuration)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		statement, err := backfillStatement(quer, start, chunkEnd)
+		if err != nil {
+			return fmt.Errorf("unable to backfill continuous query '%s': %w", name, err)
+		}
+
+		tflog.Info(ctx, "backfilling continuous query", map[string]interface{}{
+			"continuous_query": name,
+			"database":         database,
+			"chunk":            i + 1,
+			"chunks":           chunks,
+			"start":            start.Format(time.RFC3339),
+			"end":              chunkEnd.Format(time.RFC3339),
+		})
+
+		if err := execOn(ctx, conn, database, statement); err != nil {
+			return fmt.Errorf("unable to backfill continuous query '%s' from %s to %s: %w", name, start.Format(time.RFC3339), chunkEnd.Format(time.RFC3339), err)
+		}
+
+		start = chunkEnd
 	}
-	if resp.Err != nil {
-		return resp.Err
+
+	return nil
+}
+
+// backfillStart returns the start of the first chunk covering since before
+// end. Chunks are aligned on the Unix epoch shifted by the GROUP BY offset,
+// like the GROUP BY intervals, so that a chunk never splits an interval.
+func backfillStart(end time.Time, since, chunk, offset time.Duration) time.Time {
+	start := end.Add(-since - offset).UnixNano()
+	return time.Unix(0, (start/int64(chunk))*int64(chunk)).UTC().Add(offset)
+}
+
+var (
+	backfillGroupBy = regexp.MustCompile(`(?i)\bGROUP\s+BY\b`)
+	backfillWhere   = regexp.MustCompile(`(?i)\bWHERE\b`)
+	backfillTime    = regexp.MustCompile(`(?i)\btime\s*\(\s*(\w+)\s*(?:,\s*(-?\w+)\s*)?\)`)
+)
+
+// backfillOffset checks that chunk is a multiple of the GROUP BY time()
+// interval of a continuous query, and returns its offset within the interval.
+func backfillOffset(quer, chunk string) (time.Duration, error) {
+	groupBy := backfillGroupBy.FindStringIndex(maskQuoted(quer))
+	if groupBy == nil {
+		return 0, fmt.Errorf("missing GROUP BY clause in %s", quer)
 	}
 
-	d.SetId(fmt.Sprintf("%s:%s", name, database))
+	match := backfillTime.FindStringSubmatch(quer[groupBy[1]:])
+	if match == nil {
+		return 0, fmt.Errorf("missing GROUP BY time() interval in %s", quer)
+	}
 
-	err = readContinuousQuery(d, meta)
+	interval, err := influxql.ParseDuration(match[1])
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid GROUP BY time() interval %q", match[1])
+	}
+
+	chunkDuration, err := influxql.ParseDuration(chunk)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	// check that cq is created
-	if d.Id() == "" {
-		return fmt.Errorf("unable to create continuous query '%s', check your sql query", name)
+	if chunkDuration%interval != 0 {
+		return 0, fmt.Errorf("backfill chunk %s is not a multiple of the GROUP BY time() interval %s", chunk, match[1])
 	}
 
-	return nil
+	if match[2] == "" {
+		return 0, nil
+	}
+
+	offset, err := influxql.ParseDuration(strings.TrimPrefix(match[2], "-"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid GROUP BY time() offset %q", match[2])
+	}
+	if strings.HasPrefix(match[2], "-") {
+		offset = -offset
+	}
+
+	return (offset%interval + interval) % interval, nil
+}
+
+// backfillStatement restricts the SELECT body of a continuous query to the
+// time range [start, end), keeping its own WHERE condition.
+func backfillStatement(quer string, start, end time.Time) (string, error) {
+	masked := maskQuoted(quer)
+
+	groupBy := backfillGroupBy.FindStringIndex(masked)
+	if groupBy == nil {
+		return "", fmt.Errorf("missing GROUP BY clause in %s", quer)
+	}
+
+	timeRange := fmt.Sprintf("time >= %s AND time < %s", influxql.QuoteString(start.Format(time.RFC3339Nano)), influxql.QuoteString(end.Format(time.RFC3339Nano)))
+
+	where := backfillWhere.FindStringIndex(masked[:groupBy[0]])
+	if where == nil {
+		return fmt.Sprintf("%s WHERE %s %s", strings.TrimRight(quer[:groupBy[0]], " "), timeRange, quer[groupBy[0]:]), nil
+	}
+
+	condition := strings.TrimSpace(quer[where[1]:groupBy[0]])
+	return fmt.Sprintf("%s WHERE %s AND (%s) %s", strings.TrimRight(quer[:where[0]], " "), timeRange, condition, quer[groupBy[0]:]), nil
 }
 
-func readContinuousQuery(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
-	name, database, err := continuousQueryId(d.Id())
+// maskQuoted returns s with the content of its string literals, quoted
+// identifiers and parentheses replaced by spaces, so that the keywords found in
+// it are clauses of the statement itself.
+func maskQuoted(s string) string {
+	masked := []byte(s)
+
+	var quote byte
+	depth := 0
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			masked[i] = ' '
+			if c == '\\' && i+1 < len(masked) {
+				i++
+				masked[i] = ' '
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth > 0:
+			masked[i] = ' '
+		}
+	}
+
+	return string(masked)
+}
+
+func execCreateContinuousQuery(ctx context.Context, conn *connection, name, database, resample, quer string) error {
+	if resample == "" {
+		return exec(ctx, conn, fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s BEGIN %s END", influxql.QuoteIdent(name), influxql.QuoteIdent(database), quer))
+	}
+	return exec(ctx, conn, fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s RESAMPLE %s BEGIN %s END", influxql.QuoteIdent(name), influxql.QuoteIdent(database), resample, quer))
+}
+
+func readContinuousQuery(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
+	database, name, err := parseContinuousQueryId(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// InfluxDB doesn't have a command to check the existence of a single
 	// ContinuousQuery, so we instead must read the list of all ContinuousQuerys and see
 	// if ours is present in it.
-	query := client.Query{
-		Command: "SHOW CONTINUOUS QUERIES",
+	continuousQueries, err := showContinuousQueries(ctx, conn)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	resp, err := conn.Query(query)
+	for _, continuousQuery := range continuousQueries {
+		if continuousQuery["database"] == database && continuousQuery["name"] == name {
+			d.Set("name", name)
+			d.Set("database", database)
+
+			return diag.FromErr(readContinuousQueryDefinition(d, continuousQuery["definition"]))
+		}
+	}
+
+	// If we fell out here then we didn't find our ContinuousQuery in the list.
+	d.SetId("")
+
+	return nil
+}
+
+// showContinuousQueries returns the database, name and definition of all
+// continuous queries.
+func showContinuousQueries(ctx context.Context, conn *connection) ([]map[string]string, error) {
+	resp, err := query(ctx, conn, "SHOW CONTINUOUS QUERIES")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if resp.Err != nil {
-		return resp.Err
+
+	if resp.Results[0].Err != nil {
+		return nil, resp.Results[0].Err
 	}
 
+	continuousQueries := []map[string]string{}
 	for _, series := range resp.Results[0].Series {
-		if series.Name == database {
-			for _, result := range series.Values {
-				if result[0].(string) == name {
-					d.Set("name", name)
-					d.Set("database", database)
-
-					return nil
-				}
-			}
+		for _, result := range series.Values {
+			continuousQueries = append(continuousQueries, map[string]string{
+				"database":   series.Name,
+				"name":       result[0].(string),
+				"definition": result[1].(string),
+			})
 		}
 	}
 
-	// If we fell out here then we didn't find our ContinuousQuery in the list.
-	d.SetId("")
+	return continuousQueries, nil
+}
+
+// readContinuousQueryDefinition compares the definition stored by the server
+// with the one recorded at the last apply. The server rewrites the statement
+// (qualified measurements, normalized durations), so query and resample keep
+// their configured text unless the definition changed behind our back.
+func readContinuousQueryDefinition(d *schema.ResourceData, definition string) error {
+	previous := d.Get("definition").(string)
+	d.Set("definition", definition)
+
+	// an empty previous definition was not recorded yet: just created or
+	// updated, or written by an older version of the provider.
+	if d.Get("query").(string) != "" && (previous == "" || previous == definition) {
+		return nil
+	}
+
+	resample, quer, err := parseContinuousQueryDefinition(definition)
+	if err != nil {
+		return err
+	}
 
+	d.Set("query", quer)
+	d.Set("resample", resample)
 	return nil
 }
 
-func deleteContinuousQuery(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+var continuousQueryDefinition = regexp.MustCompile(`(?s)^CREATE CONTINUOUS QUERY (?:"(?:[^"\\]|\\.)*"|\S+) ON (?:"(?:[^"\\]|\\.)*"|\S+) (?:RESAMPLE (.*?) )?BEGIN (.*) END$`)
+
+// parseContinuousQueryDefinition splits a definition returned by SHOW
+// CONTINUOUS QUERIES into the body of its RESAMPLE clause and its SELECT statement.
+func parseContinuousQueryDefinition(definition string) (string, string, error) {
+	matches := continuousQueryDefinition.FindStringSubmatch(definition)
+	if matches == nil {
+		return "", "", fmt.Errorf("unexpected continuous query definition: %s", definition)
+	}
+
+	return matches[1], matches[2], nil
+}
+
+func updateContinuousQuery(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 	name := d.Get("name").(string)
 	database := d.Get("database").(string)
 
-	queryStr := fmt.Sprintf("DROP CONTINUOUS QUERY %q ON %q", name, database)
-	query := client.Query{
-		Command: queryStr,
+	if d.HasChanges("query", "resample") {
+		oldResample, resample := d.GetChange("resample")
+		oldQuery, quer := d.GetChange("query")
+
+		err := replaceContinuousQuery(ctx, conn, name, database, oldResample.(string), oldQuery.(string), resample.(string), quer.(string))
+		if err != nil {
+			// the server still runs the previous definition, keep it in the state
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+
+		// the recorded definition is the one of the dropped continuous query
+		d.Set("definition", "")
 	}
 
-	resp, err := conn.Query(query)
-	if err != nil {
+	// statements were only recorded, there is nothing to read back
+	if conn.config.DryRun {
+		return nil
+	}
+
+	diags := readContinuousQuery(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+	// check that cq is recreated
+	if d.Id() == "" {
+		return diag.Errorf("unable to update continuous query '%s', check your sql query", name)
+	}
+
+	return nil
+}
+
+// replaceContinuousQuery replaces the definition of a continuous query.
+// InfluxDB has no statement to alter a continuous query: it is dropped and
+// created again, with its previous definition when the new one fails.
+func replaceContinuousQuery(ctx context.Context, conn *connection, name, database, oldResample, oldQuery, resample, quer string) error {
+	if err := exec(ctx, conn, fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", influxql.QuoteIdent(name), influxql.QuoteIdent(database))); err != nil {
 		return err
 	}
-	if resp.Err != nil {
-		return resp.Err
+
+	err := execCreateContinuousQuery(ctx, conn, name, database, resample, quer)
+	if err == nil {
+		return nil
+	}
+
+	// the statement timeout may be the cause, the restore gets its own.
+	restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+	defer cancel()
+
+	if restoreErr := execCreateContinuousQuery(restoreCtx, conn, name, database, oldResample, oldQuery); restoreErr != nil {
+		return fmt.Errorf("unable to update continuous query '%s': %w, and restoring its previous definition failed: %s", name, err, restoreErr)
+	}
+
+	return fmt.Errorf("unable to update continuous query '%s', its previous definition was restored: %w", name, err)
+}
+
+func customizeContinuousQueryDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// the backfill only runs on creation
+	if v, ok := d.GetOk("backfill"); ok && d.Id() == "" && d.NewValueKnown("query") && d.NewValueKnown("backfill.0.chunk") {
+		backfill := v.([]interface{})[0].(map[string]interface{})
+		if _, err := backfillOffset(d.Get("query").(string), backfill["chunk"].(string)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("query", "resample") {
+		return d.SetNewComputed("definition")
+	}
+	return nil
+}
+
+func deleteContinuousQuery(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
+	name := d.Get("name").(string)
+	database := d.Get("database").(string)
+
+	if err := exec(ctx, conn, fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", influxql.QuoteIdent(name), influxql.QuoteIdent(database))); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func continuousQueryId(id string) (string, string, error) {
-	parts := strings.SplitN(id, ":", 2)
+func importContinuousQuery(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, err := parseContinuousQueryId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	// query and resample are read from the definition when query is empty
+	d.SetId(continuousQueryId(database, name))
+	d.Set("name", name)
+	d.Set("database", database)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// continuousQueryId returns DB/NAME with both parts path escaped, so that any
+// identifier round-trips through parseContinuousQueryId.
+func continuousQueryId(database, name string) string {
+	return joinId(database, name)
+}
 
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return "", "", fmt.Errorf("unexpected format of ID (%s), expected NAME:DB-ID", id)
+func parseContinuousQueryId(id string) (string, string, error) {
+	parts, err := splitId(id, 2, "DB/NAME")
+	if err != nil {
+		return "", "", err
 	}
 
 	return parts[0], parts[1], nil
 }
+
+// resourceContinuousQueryV0 is the schema of the continuous queries whose ID
+// was NAME:DB, ambiguous when the name contains ':'.
+func resourceContinuousQueryV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"resample": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "",
+			},
+		},
+	}
+}
+
+// upgradeContinuousQueryV0 rebuilds the ID from the name and database
+// attributes instead of splitting the old NAME:DB ID.
+func upgradeContinuousQueryV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	name, _ := rawState["name"].(string)
+	database, _ := rawState["database"].(string)
+	if name == "" || database == "" {
+		return nil, fmt.Errorf("unable to upgrade continuous query %v: name and database are required", rawState["id"])
+	}
+
+	rawState["id"] = continuousQueryId(database, name)
+	return rawState, nil
+}
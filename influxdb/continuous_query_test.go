@@ -1,13 +1,18 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/influxdata/influxdb/client"
 )
 
@@ -16,7 +21,7 @@ func TestAccInfluxDBContiuousQuery_basic(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccContiuousQueryBasicConfig(rName),
@@ -27,6 +32,14 @@ func TestAccInfluxDBContiuousQuery_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "query", "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)"),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s", rName, rName),
+				ImportStateVerify: true,
+				// imported from the definition, qualified by the server
+				ImportStateVerifyIgnore: []string{"query"},
+			},
 		},
 	})
 }
@@ -36,7 +49,7 @@ func TestAccInfluxDBContiuousQuery_resample(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccContiuousQueryResampleConfig(rName),
@@ -52,12 +65,361 @@ func TestAccInfluxDBContiuousQuery_resample(t *testing.T) {
 	})
 }
 
+func TestAccInfluxDBContiuousQuery_update(t *testing.T) {
+	resourceName := "influxdb_continuous_query.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContiuousQueryBasicConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckContiuousQueryExists(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "definition"),
+				),
+			},
+			{
+				Config: testAccContiuousQueryResampleConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckContiuousQueryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "query", "SELECT min(mouse) INTO min_mouse_resampled FROM zoo GROUP BY time(30m)"),
+					resource.TestCheckResourceAttr(resourceName, "resample", "EVERY 30m FOR 90m"),
+				),
+			},
+			{
+				// edit the continuous query behind terraform's back
+				PreConfig: func() {
+					conn := testAccProvider.Meta().(*connection)
+					for _, command := range []string{
+						fmt.Sprintf("DROP CONTINUOUS QUERY %q ON %q", rName, rName),
+						fmt.Sprintf("CREATE CONTINUOUS QUERY %q ON %q BEGIN SELECT max(mouse) INTO max_mouse FROM zoo GROUP BY time(30m) END", rName, rName),
+					} {
+						if err := exec(context.Background(), conn, command); err != nil {
+							t.Fatal(err)
+						}
+					}
+				},
+				Config:             testAccContiuousQueryResampleConfig(rName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBContiuousQuery_updateRollback(t *testing.T) {
+	resourceName := "influxdb_continuous_query.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContiuousQueryBasicConfig(rName),
+				Check:  testAccCheckContiuousQueryExists(resourceName),
+			},
+			{
+				// a continuous query requires a GROUP BY time() clause
+				Config: fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_continuous_query" "test" {
+  name     = %[1]q
+  database = influxdb_database.test.name
+  query    = "SELECT min(mouse) INTO min_mouse FROM zoo"
+}
+`, rName),
+				ExpectError: regexp.MustCompile("its previous definition was restored"),
+			},
+			{
+				// the previous definition still runs and is still in the state
+				Config: testAccContiuousQueryBasicConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckContiuousQueryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "query", "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)"),
+				),
+			},
+		},
+	})
+}
+
+func TestReplaceContinuousQuery(t *testing.T) {
+	for _, tc := range []struct {
+		failRestore bool
+		expected    string
+	}{
+		{expected: "its previous definition was restored"},
+		{failRestore: true, expected: "restoring its previous definition failed"},
+	} {
+		var statements []string
+		server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+			statement := r.URL.Query().Get("q")
+			statements = append(statements, statement)
+
+			if strings.Contains(statement, "max(mouse)") || (tc.failRestore && strings.Contains(statement, "min(mouse)")) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"error parsing query"}`))
+				return
+			}
+			w.Write([]byte(`{"results":[{"statement_id":0}]}`))
+		})
+
+		err := replaceContinuousQuery(context.Background(), testConnection(t, server.URL), "minnie", "telegraf",
+			"", "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)",
+			"EVERY 1h", "SELECT max(mouse) INTO max_mouse FROM zoo GROUP BY time(30m)")
+		server.Close()
+
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("expected an error with %q, got: %v", tc.expected, err)
+		}
+
+		expected := []string{
+			`DROP CONTINUOUS QUERY "minnie" ON "telegraf"`,
+			`CREATE CONTINUOUS QUERY "minnie" ON "telegraf" RESAMPLE EVERY 1h BEGIN SELECT max(mouse) INTO max_mouse FROM zoo GROUP BY time(30m) END`,
+			`CREATE CONTINUOUS QUERY "minnie" ON "telegraf" BEGIN SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m) END`,
+		}
+		if strings.Join(statements, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("expected statements:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(statements, "\n"))
+		}
+	}
+}
+
+func TestParseContinuousQueryDefinition(t *testing.T) {
+	for _, tc := range []struct {
+		definition string
+		resample   string
+		query      string
+	}{
+		{
+			definition: `CREATE CONTINUOUS QUERY minnie ON telegraf BEGIN SELECT min(mouse) INTO telegraf.autogen.min_mouse FROM telegraf.autogen.zoo GROUP BY time(30m) END`,
+			query:      `SELECT min(mouse) INTO telegraf.autogen.min_mouse FROM telegraf.autogen.zoo GROUP BY time(30m)`,
+		},
+		{
+			definition: `CREATE CONTINUOUS QUERY minnie ON "tf-acc-test" RESAMPLE EVERY 30m FOR 1h30m BEGIN SELECT min(mouse) INTO "tf-acc-test".autogen.min_mouse FROM "tf-acc-test".autogen.zoo GROUP BY time(30m) END`,
+			resample:   `EVERY 30m FOR 1h30m`,
+			query:      `SELECT min(mouse) INTO "tf-acc-test".autogen.min_mouse FROM "tf-acc-test".autogen.zoo GROUP BY time(30m)`,
+		},
+		{
+			definition: `CREATE CONTINUOUS QUERY "BEGIN \"END\"" ON "my db" RESAMPLE FOR 2h BEGIN SELECT count(v) AS count INTO "my db".rp.:MEASUREMENT FROM "my db".raw./.*/ WHERE src = 'BEGIN END' GROUP BY time(1d), * END`,
+			resample:   `FOR 2h`,
+			query:      `SELECT count(v) AS count INTO "my db".rp.:MEASUREMENT FROM "my db".raw./.*/ WHERE src = 'BEGIN END' GROUP BY time(1d), *`,
+		},
+	} {
+		resample, query, err := parseContinuousQueryDefinition(tc.definition)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.definition, err)
+		}
+		if resample != tc.resample {
+			t.Errorf("%s: expected resample %q, got %q", tc.definition, tc.resample, resample)
+		}
+		if query != tc.query {
+			t.Errorf("%s: expected query %q, got %q", tc.definition, tc.query, query)
+		}
+	}
+
+	if _, _, err := parseContinuousQueryDefinition("SELECT 1"); err == nil {
+		t.Error("expected an error on a statement which is not a continuous query")
+	}
+}
+
+func TestContinuousQueryId(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"telegraf", "minnie"},
+		{"a:b", "c:d"},
+		{"my/db", "cq/with/slashes"},
+		{"100%", "%2F"},
+		{"über db", `quo"te`},
+	} {
+		id := continuousQueryId(tc[0], tc[1])
+
+		database, name, err := parseContinuousQueryId(id)
+		if err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if database != tc[0] || name != tc[1] {
+			t.Errorf("%s: expected %q and %q, got %q and %q", id, tc[0], tc[1], database, name)
+		}
+	}
+
+	for _, id := range []string{"minnie:telegraf", "telegraf/", "a/b/c", "telegraf/%zz"} {
+		if _, _, err := parseContinuousQueryId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
+
+func TestUpdateContinuousQuery_backfillOnly(t *testing.T) {
+	var statements []string
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		statements = append(statements, r.URL.Query().Get("q"))
+		// the query was changed behind our back
+		w.Write([]byte(`{"results":[{"statement_id":0,"series":[{"name":"telegraf","columns":["name","query"],"values":[["minnie","CREATE CONTINUOUS QUERY minnie ON telegraf BEGIN SELECT max(mouse) INTO telegraf.autogen.max_mouse FROM telegraf.autogen.zoo GROUP BY time(30m) END"]]}]}]}`))
+	})
+	defer server.Close()
+
+	d := resourceContinuousQuery().Data(&sdkterraform.InstanceState{
+		ID: "telegraf/minnie",
+		Attributes: map[string]string{
+			"id":         "telegraf/minnie",
+			"name":       "minnie",
+			"database":   "telegraf",
+			"query":      "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)",
+			"resample":   "",
+			"definition": "CREATE CONTINUOUS QUERY minnie ON telegraf BEGIN SELECT min(mouse) INTO telegraf.autogen.min_mouse FROM telegraf.autogen.zoo GROUP BY time(30m) END",
+		},
+	})
+	d.Set("backfill", []interface{}{map[string]interface{}{"since": "2d", "chunk": "1d"}})
+
+	if diags := updateContinuousQuery(context.Background(), d, testConnection(t, server.URL)); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	if len(statements) != 1 || statements[0] != "SHOW CONTINUOUS QUERIES" {
+		t.Errorf("expected only SHOW CONTINUOUS QUERIES, got %q", statements)
+	}
+	// the recorded definition is kept, so that the drift shows up
+	if actual := d.Get("query").(string); !strings.Contains(actual, "max(mouse)") {
+		t.Errorf("expected the query changed on the server, got %s", actual)
+	}
+}
+
+func TestUpgradeContinuousQueryV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":       "a:b:telegraf",
+		"name":     "a:b",
+		"database": "telegraf",
+		"query":    "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)",
+		"resample": "",
+	}
+
+	upgraded, err := upgradeContinuousQueryV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upgraded["id"] != "telegraf/a:b" {
+		t.Errorf("expected ID telegraf/a:b, got %s", upgraded["id"])
+	}
+}
+
+func TestAccInfluxDBContiuousQuery_backfill(t *testing.T) {
+	resourceName := "influxdb_continuous_query.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContiuousQueryBackfillConfig(rName, time.Now().Add(-24*time.Hour).Unix()),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckContiuousQueryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "backfill.0.since", "2d"),
+					resource.TestCheckResourceAttr(resourceName, "backfill.0.chunk", "12h"),
+					testAccCheckBackfilled(rName),
+				),
+			},
+		},
+	})
+}
+
+func TestBackfillStatement(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	timeRange := "time >= '2024-01-01T00:00:00Z' AND time < '2024-01-02T00:00:00Z'"
+
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)",
+			expected: "SELECT min(mouse) INTO min_mouse FROM zoo WHERE " + timeRange + " GROUP BY time(30m)",
+		},
+		{
+			query:    "SELECT count(arrival_time) AS count INTO tooling_rp.:MEASUREMENT FROM raw_default_rp./.*/ WHERE tech_source_id = 'S2' OR project = 'x' group by time(1d), project, tech_source_id",
+			expected: "SELECT count(arrival_time) AS count INTO tooling_rp.:MEASUREMENT FROM raw_default_rp./.*/ WHERE " + timeRange + " AND (tech_source_id = 'S2' OR project = 'x') group by time(1d), project, tech_source_id",
+		},
+		{
+			query:    `SELECT mean("where") INTO "group by" FROM "cpu" WHERE host = 'WHERE it\'s GROUP BY' GROUP BY time(1h) fill(none)`,
+			expected: `SELECT mean("where") INTO "group by" FROM "cpu" WHERE ` + timeRange + ` AND (host = 'WHERE it\'s GROUP BY') GROUP BY time(1h) fill(none)`,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := backfillStatement(c.query, start, end)
+		if err != nil {
+			t.Errorf("backfillStatement(%q): unexpected error %s", c.query, err)
+		} else if actual != c.expected {
+			t.Errorf("backfillStatement(%q):\nexpected %s\ngot      %s", c.query, c.expected, actual)
+		}
+	}
+
+	if _, err := backfillStatement("SELECT min(mouse) INTO min_mouse FROM zoo", start, end); err == nil {
+		t.Error("expected an error without GROUP BY clause")
+	}
+}
+
+func TestBackfillStart(t *testing.T) {
+	end := time.Date(2024, 1, 10, 12, 10, 0, 0, time.UTC)
+
+	cases := []struct {
+		since, chunk, offset time.Duration
+		expected             time.Time
+	}{
+		{48 * time.Hour, 24 * time.Hour, 0, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		// weeks start on Thursday, as the Unix epoch
+		{7 * 24 * time.Hour, 7 * 24 * time.Hour, 0, time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC)},
+		{7 * 24 * time.Hour, 7 * 24 * time.Hour, 4 * 24 * time.Hour, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Hour, 30 * time.Minute, 15 * time.Minute, time.Date(2024, 1, 10, 10, 45, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		if actual := backfillStart(end, c.since, c.chunk, c.offset); !actual.Equal(c.expected) {
+			t.Errorf("backfillStart(%s, %s, %s): expected %s, got %s", c.since, c.chunk, c.offset, c.expected, actual)
+		}
+	}
+}
+
+func TestBackfillOffset(t *testing.T) {
+	cases := []struct {
+		query, chunk string
+		expected     time.Duration
+	}{
+		{"SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)", "1d", 0},
+		{"SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(1w, 4d), zone", "2w", 4 * 24 * time.Hour},
+		{"SELECT min(mouse) INTO min_mouse FROM zoo group by zone, TIME(1h,-15m)", "1d", 45 * time.Minute},
+	}
+
+	for _, c := range cases {
+		actual, err := backfillOffset(c.query, c.chunk)
+		if err != nil {
+			t.Errorf("backfillOffset(%q, %s): unexpected error %s", c.query, c.chunk, err)
+		} else if actual != c.expected {
+			t.Errorf("backfillOffset(%q, %s): expected %s, got %s", c.query, c.chunk, c.expected, actual)
+		}
+	}
+
+	for _, c := range [][2]string{
+		{"SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(1w)", "1d"},
+		{"SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(7h)", "1d"},
+		{"SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY zone", "1d"},
+		{"SELECT min(mouse) INTO min_mouse FROM zoo WHERE time > now() - 1d", "1d"},
+	} {
+		if _, err := backfillOffset(c[0], c[1]); err == nil {
+			t.Errorf("backfillOffset(%q, %s): expected an error", c[0], c[1])
+		}
+	}
+}
+
 func TestAccContiuousQueryConfig(t *testing.T) {
 	resourceName := "influxdb_continuous_query.test"
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccContiuousQueryConfig(rName),
@@ -77,7 +439,7 @@ func TestAccContiuousWithFailureQueryConfig(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccContiuousQueryWithFailureConfig(rName),
@@ -100,13 +462,13 @@ func testAccCheckContiuousQueryExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No ContiuousQuery id set")
 		}
 
-		conn := testAccProvider.Meta().(*client.Client)
+		conn := testAccProvider.Meta().(*connection)
 
 		query := client.Query{
 			Command: "SHOW CONTINUOUS QUERIES",
 		}
 
-		resp, err := conn.Query(query)
+		resp, err := conn.QueryContext(context.Background(), query)
 		if err != nil {
 			return err
 		}
@@ -190,6 +552,50 @@ resource "influxdb_continuous_query" "test" {
 `, rName)
 }
 
+func testAccCheckBackfilled(database string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		resp, err := query(context.Background(), conn, fmt.Sprintf("SELECT count(min) FROM %q.autogen.min_mouse", database))
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Results) == 0 || len(resp.Results[0].Series) == 0 {
+			return fmt.Errorf("No point backfilled in %s.autogen.min_mouse", database)
+		}
+
+		return nil
+	}
+}
+
+func testAccContiuousQueryBackfillConfig(rName string, timestamp int64) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_points" "test" {
+  database  = influxdb_database.test.name
+  precision = "s"
+  body      = "zoo mouse=3 %[2]d"
+}
+
+resource "influxdb_continuous_query" "test" {
+  name     = %[1]q
+  database = influxdb_database.test.name
+  query    = "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)"
+
+  backfill {
+    since = "2d"
+    chunk = "12h"
+  }
+
+  depends_on = [influxdb_points.test]
+}
+`, rName, timestamp)
+}
+
 func testAccContiuousQueryResampleConfig(rName string) string {
 	return fmt.Sprintf(`
 resource "influxdb_database" "test" {
@@ -0,0 +1,225 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &continuousQueriesDataSource{}
+	_ datasource.DataSourceWithConfigure = &continuousQueriesDataSource{}
+)
+
+func newContinuousQueriesDataSource() datasource.DataSource {
+	return &continuousQueriesDataSource{}
+}
+
+// continuousQueriesDataSource lists the continuous queries of each database.
+type continuousQueriesDataSource struct {
+	conn *connection
+}
+
+type continuousQueriesDataSourceModel struct {
+	ID        types.String                     `tfsdk:"id"`
+	Database  types.String                     `tfsdk:"database"`
+	Databases []continuousQueriesDatabaseModel `tfsdk:"databases"`
+	Timeouts  timeouts.Value                   `tfsdk:"timeouts"`
+}
+
+type continuousQueriesDatabaseModel struct {
+	Name              types.String           `tfsdk:"name"`
+	ContinuousQueries []continuousQueryModel `tfsdk:"continuous_queries"`
+}
+
+type continuousQueryModel struct {
+	Name            types.String `tfsdk:"name"`
+	Definition      types.String `tfsdk:"definition"`
+	Query           types.String `tfsdk:"query"`
+	Into            types.String `tfsdk:"into"`
+	GroupByInterval types.String `tfsdk:"group_by_interval"`
+	Resample        types.String `tfsdk:"resample"`
+	ResampleEvery   types.String `tfsdk:"resample_every"`
+	ResampleFor     types.String `tfsdk:"resample_for"`
+}
+
+func (d *continuousQueriesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_continuous_queries"
+}
+
+func (d *continuousQueriesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the continuous queries of each database with their parsed definition.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The database when set, continuous_queries otherwise.",
+			},
+			"database": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the continuous queries of this database.",
+			},
+			"databases": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name": types.StringType,
+						"continuous_queries": types.ListType{
+							ElemType: types.ObjectType{
+								AttrTypes: map[string]attr.Type{
+									"name":              types.StringType,
+									"definition":        types.StringType,
+									"query":             types.StringType,
+									"into":              types.StringType,
+									"group_by_interval": types.StringType,
+									"resample":          types.StringType,
+									"resample_every":    types.StringType,
+									"resample_for":      types.StringType,
+								},
+							},
+						},
+					},
+				},
+				Description: "The databases having continuous queries, with their continuous queries.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *continuousQueriesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *continuousQueriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config continuousQueriesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	continuousQueries, err := showContinuousQueries(ctx, d.conn)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading continuous queries", err.Error())
+		return
+	}
+
+	config.ID = types.StringValue("continuous_queries")
+	if !config.Database.IsNull() {
+		config.ID = config.Database
+	}
+
+	config.Databases = []continuousQueriesDatabaseModel{}
+	for _, continuousQuery := range continuousQueries {
+		database := continuousQuery["database"]
+		if !config.Database.IsNull() && database != config.Database.ValueString() {
+			continue
+		}
+
+		model, err := parseContinuousQueryModel(continuousQuery["name"], continuousQuery["definition"])
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading continuous queries", err.Error())
+			return
+		}
+
+		// SHOW CONTINUOUS QUERIES returns the queries grouped by database
+		last := len(config.Databases) - 1
+		if last < 0 || config.Databases[last].Name.ValueString() != database {
+			config.Databases = append(config.Databases, continuousQueriesDatabaseModel{
+				Name: types.StringValue(database),
+			})
+			last++
+		}
+		config.Databases[last].ContinuousQueries = append(config.Databases[last].ContinuousQueries, model)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
+
+// parseContinuousQueryModel splits a definition returned by SHOW CONTINUOUS
+// QUERIES into the clauses exposed by the data source.
+func parseContinuousQueryModel(name, definition string) (continuousQueryModel, error) {
+	resample, quer, err := parseContinuousQueryDefinition(definition)
+	if err != nil {
+		return continuousQueryModel{}, err
+	}
+
+	every, period, err := parseResample(resample)
+	if err != nil {
+		return continuousQueryModel{}, err
+	}
+
+	into, interval := parseContinuousQuerySelect(quer)
+
+	return continuousQueryModel{
+		Name:            types.StringValue(name),
+		Definition:      types.StringValue(definition),
+		Query:           types.StringValue(quer),
+		Into:            types.StringValue(into),
+		GroupByInterval: types.StringValue(interval),
+		Resample:        types.StringValue(resample),
+		ResampleEvery:   types.StringValue(every),
+		ResampleFor:     types.StringValue(period),
+	}, nil
+}
+
+var (
+	resampleClause = regexp.MustCompile(`(?i)^(?:EVERY\s+(\S+))?\s*(?:FOR\s+(\S+))?$`)
+
+	selectInto          = regexp.MustCompile(`(?i)\bINTO\s+`)
+	selectFrom          = regexp.MustCompile(`(?i)\s+FROM\b`)
+	selectGroupByTime   = regexp.MustCompile(`(?i)\bGROUP\s+BY\b.*?\btime\(`)
+	selectGroupByLength = regexp.MustCompile(`^\s*([^,)]*?)\s*[,)]`)
+)
+
+// parseResample returns the EVERY and FOR durations of the body of a RESAMPLE
+// clause, empty when not set.
+func parseResample(resample string) (string, string, error) {
+	matches := resampleClause.FindStringSubmatch(strings.TrimSpace(resample))
+	if matches == nil {
+		return "", "", fmt.Errorf("unexpected RESAMPLE clause: %s", resample)
+	}
+
+	return matches[1], matches[2], nil
+}
+
+// parseContinuousQuerySelect returns the INTO target and the GROUP BY time
+// interval of the SELECT statement of a continuous query.
+func parseContinuousQuerySelect(quer string) (string, string) {
+	masked := maskQuoted(quer)
+
+	var into string
+	if start := selectInto.FindStringIndex(masked); start != nil {
+		if end := selectFrom.FindStringIndex(masked[start[1]:]); end != nil {
+			into = quer[start[1] : start[1]+end[0]]
+		}
+	}
+
+	var interval string
+	if groupBy := selectGroupByTime.FindStringIndex(masked); groupBy != nil {
+		if length := selectGroupByLength.FindStringSubmatch(quer[groupBy[1]:]); length != nil {
+			interval = length[1]
+		}
+	}
+
+	return into, interval
+}
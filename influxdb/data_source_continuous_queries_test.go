@@ -0,0 +1,101 @@
+package influxdb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBContinuousQueriesDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_continuous_queries.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContinuousQueriesDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.name", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.continuous_queries.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.continuous_queries.0.name", "minnie"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.continuous_queries.0.group_by_interval", "30m"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.continuous_queries.0.resample_every", "30m"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.continuous_queries.0.resample_for", "1h30m"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "databases.0.continuous_queries.0.definition", "influxdb_continuous_query.minnie", "definition"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseContinuousQueryModel(t *testing.T) {
+	for _, tc := range []struct {
+		definition string
+		into       string
+		interval   string
+		every      string
+		period     string
+	}{
+		{
+			definition: `CREATE CONTINUOUS QUERY minnie ON telegraf BEGIN SELECT min(mouse) INTO telegraf.autogen.min_mouse FROM telegraf.autogen.zoo GROUP BY time(30m) END`,
+			into:       `telegraf.autogen.min_mouse`,
+			interval:   `30m`,
+		},
+		{
+			definition: `CREATE CONTINUOUS QUERY minnie ON "tf-acc-test" RESAMPLE EVERY 30m FOR 1h30m BEGIN SELECT min(mouse) INTO "tf-acc-test".autogen.min_mouse FROM "tf-acc-test".autogen.zoo GROUP BY time(30m, 5m), host END`,
+			into:       `"tf-acc-test".autogen.min_mouse`,
+			interval:   `30m`,
+			every:      `30m`,
+			period:     `1h30m`,
+		},
+		{
+			definition: `CREATE CONTINUOUS QUERY "BEGIN \"END\"" ON "my db" RESAMPLE FOR 2h BEGIN SELECT count(v) AS count INTO "my db"."from into".:MEASUREMENT FROM "my db".raw./.*/ WHERE src = 'GROUP BY time(1h)' GROUP BY *, time(1d) END`,
+			into:       `"my db"."from into".:MEASUREMENT`,
+			interval:   `1d`,
+			period:     `2h`,
+		},
+	} {
+		model, err := parseContinuousQueryModel("cq", tc.definition)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.definition, err)
+		}
+		if got := model.Into.ValueString(); got != tc.into {
+			t.Errorf("%s: expected into %q, got %q", tc.definition, tc.into, got)
+		}
+		if got := model.GroupByInterval.ValueString(); got != tc.interval {
+			t.Errorf("%s: expected group by interval %q, got %q", tc.definition, tc.interval, got)
+		}
+		if got := model.ResampleEvery.ValueString(); got != tc.every {
+			t.Errorf("%s: expected resample every %q, got %q", tc.definition, tc.every, got)
+		}
+		if got := model.ResampleFor.ValueString(); got != tc.period {
+			t.Errorf("%s: expected resample for %q, got %q", tc.definition, tc.period, got)
+		}
+	}
+}
+
+func testAccContinuousQueriesDataSourceConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %q
+}
+
+resource "influxdb_continuous_query" "minnie" {
+  name     = "minnie"
+  database = influxdb_database.test.name
+  query    = "SELECT min(mouse) INTO min_mouse FROM zoo GROUP BY time(30m)"
+  resample = "EVERY 30m FOR 1h30m"
+}
+
+data "influxdb_continuous_queries" "test" {
+  database = influxdb_database.test.name
+
+  depends_on = [influxdb_continuous_query.minnie]
+}
+`, rName)
+}
@@ -0,0 +1,110 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &databaseDataSource{}
+	_ datasource.DataSourceWithConfigure = &databaseDataSource{}
+)
+
+func newDatabaseDataSource() datasource.DataSource {
+	return &databaseDataSource{}
+}
+
+// databaseDataSource reads the retention policies of an existing database.
+type databaseDataSource struct {
+	conn *connection
+}
+
+type databaseDataSourceModel struct {
+	ID                     types.String           `tfsdk:"id"`
+	Name                   types.String           `tfsdk:"name"`
+	RetentionPolicies      []retentionPolicyModel `tfsdk:"retention_policies"`
+	DefaultRetentionPolicy types.String           `tfsdk:"default_retention_policy"`
+	Timeouts               timeouts.Value         `tfsdk:"timeouts"`
+}
+
+func (d *databaseDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database"
+}
+
+func (d *databaseDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing database and its retention policies.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the database.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the database.",
+			},
+			"retention_policies": schema.ListAttribute{
+				Computed:    true,
+				ElementType: retentionPolicyType,
+				Description: "The retention policies of the database, including autogen.",
+			},
+			"default_retention_policy": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the default retention policy of the database.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *databaseDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *databaseDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config databaseDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	name := config.Name.ValueString()
+
+	policies, err := retentionPolicyModels(ctx, d.conn, name)
+	if isDatabaseNotFound(err) {
+		resp.Diagnostics.AddError("Database not found", fmt.Sprintf("database '%s' does not exist", name))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading retention policies", err.Error())
+		return
+	}
+
+	config.ID = types.StringValue(name)
+	config.RetentionPolicies = policies
+	config.DefaultRetentionPolicy = types.StringValue("")
+	for _, policy := range policies {
+		if policy.Default.ValueBool() {
+			config.DefaultRetentionPolicy = policy.Name
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
@@ -0,0 +1,77 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBDatabaseDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_database.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "default_retention_policy", "1week"),
+					resource.TestCheckResourceAttr(dataSourceName, "retention_policies.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "retention_policies.*", map[string]string{
+						"name":        "autogen",
+						"duration":    "0s",
+						"replication": "1",
+						"default":     "false",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "retention_policies.*", map[string]string{
+						"name":                 "1week",
+						"duration":             "168h0m0s",
+						"shard_group_duration": "24h0m0s",
+						"default":              "true",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBDatabaseDataSource_notFound(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "influxdb_database" "test" {
+  name = "%s"
+}
+`, rName),
+				ExpectError: regexp.MustCompile("does not exist"),
+			},
+		},
+	})
+}
+
+func testAccDatabaseDataSourceConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = "%s"
+
+  retention_policies {
+    name     = "1week"
+    duration = "1w"
+    default  = true
+  }
+}
+
+data "influxdb_database" "test" {
+  name = influxdb_database.test.name
+}
+`, rName)
+}
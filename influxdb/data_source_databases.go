@@ -0,0 +1,215 @@
+package influxdb
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &databasesDataSource{}
+	_ datasource.DataSourceWithConfigure = &databasesDataSource{}
+)
+
+func newDatabasesDataSource() datasource.DataSource {
+	return &databasesDataSource{}
+}
+
+// databasesDataSource lists the databases, optionally filtered by name.
+type databasesDataSource struct {
+	conn *connection
+}
+
+type databasesDataSourceModel struct {
+	ID                    types.String    `tfsdk:"id"`
+	Include               types.String    `tfsdk:"include"`
+	Exclude               types.String    `tfsdk:"exclude"`
+	WithRetentionPolicies types.Bool      `tfsdk:"with_retention_policies"`
+	Names                 []types.String  `tfsdk:"names"`
+	Databases             []databaseModel `tfsdk:"databases"`
+	Timeouts              timeouts.Value  `tfsdk:"timeouts"`
+}
+
+type databaseModel struct {
+	Name              types.String           `tfsdk:"name"`
+	RetentionPolicies []retentionPolicyModel `tfsdk:"retention_policies"`
+}
+
+// retentionPolicyModel is a retention policy as returned by SHOW RETENTION POLICIES.
+type retentionPolicyModel struct {
+	Name               types.String `tfsdk:"name"`
+	Duration           types.String `tfsdk:"duration"`
+	ShardGroupDuration types.String `tfsdk:"shard_group_duration"`
+	Replication        types.Int64  `tfsdk:"replication"`
+	Default            types.Bool   `tfsdk:"default"`
+}
+
+// retentionPolicyType is the object type of retentionPolicyModel, nested
+// attributes are not available with protocol version 5.
+var retentionPolicyType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":                 types.StringType,
+		"duration":             types.StringType,
+		"shard_group_duration": types.StringType,
+		"replication":          types.Int64Type,
+		"default":              types.BoolType,
+	},
+}
+
+// retentionPolicyModels returns the retention policies of a database,
+// including autogen.
+func retentionPolicyModels(ctx context.Context, conn *connection, database string) ([]retentionPolicyModel, error) {
+	policies, err := showRetentionPolicies(ctx, conn, database)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]retentionPolicyModel, len(policies))
+	for i, policy := range policies {
+		models[i] = retentionPolicyModel{
+			Name:               types.StringValue(policy["name"].(string)),
+			Duration:           types.StringValue(policy["duration"].(string)),
+			ShardGroupDuration: types.StringValue(policy["shardgroupduration"].(string)),
+			Replication:        types.Int64Value(int64(policy["replication"].(int))),
+			Default:            types.BoolValue(policy["default"].(bool)),
+		}
+	}
+
+	return models, nil
+}
+
+func (d *databasesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_databases"
+}
+
+func (d *databasesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the databases of the server.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Always databases.",
+			},
+			"include": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the databases whose name matches this regex.",
+			},
+			"exclude": schema.StringAttribute{
+				Optional:    true,
+				Description: "Do not list the databases whose name matches this regex.",
+			},
+			"with_retention_policies": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether the retention policies of each database are read.",
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The names of the databases.",
+			},
+			"databases": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":               types.StringType,
+						"retention_policies": types.ListType{ElemType: retentionPolicyType},
+					},
+				},
+				Description: "The databases, with their retention policies, including autogen, when with_retention_policies is set.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *databasesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *databasesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config databasesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	include := compileFilter(config.Include, path.Root("include"), &resp.Diagnostics)
+	exclude := compileFilter(config.Exclude, path.Root("exclude"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	databases, err := showDatabases(ctx, d.conn)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading databases", err.Error())
+		return
+	}
+
+	config.ID = types.StringValue("databases")
+	config.Names = []types.String{}
+	config.Databases = []databaseModel{}
+	for _, name := range databases {
+		if !matchFilters(name, include, exclude) {
+			continue
+		}
+
+		database := databaseModel{
+			Name: types.StringValue(name),
+		}
+
+		if config.WithRetentionPolicies.ValueBool() {
+			database.RetentionPolicies, err = retentionPolicyModels(ctx, d.conn, name)
+			if err != nil {
+				resp.Diagnostics.AddError("Error reading retention policies", err.Error())
+				return
+			}
+		}
+
+		config.Names = append(config.Names, database.Name)
+		config.Databases = append(config.Databases, database)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
+
+// compileFilter compiles the regex of a filter attribute, nil when it is not set.
+func compileFilter(filter types.String, attribute path.Path, diags *diag.Diagnostics) *regexp.Regexp {
+	if filter.IsNull() {
+		return nil
+	}
+
+	re, err := regexp.Compile(filter.ValueString())
+	if err != nil {
+		diags.AddAttributeError(attribute, "Invalid regex", err.Error())
+		return nil
+	}
+
+	return re
+}
+
+// matchFilters reports whether name matches include and does not match exclude.
+func matchFilters(name string, include, exclude *regexp.Regexp) bool {
+	if include != nil && !include.MatchString(name) {
+		return false
+	}
+	return exclude == nil || !exclude.MatchString(name)
+}
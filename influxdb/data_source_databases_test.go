@@ -0,0 +1,85 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBDatabasesDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_databases.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabasesDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.0", rName+"-kept"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.name", rName+"-kept"),
+					resource.TestCheckResourceAttr(dataSourceName, "databases.0.retention_policies.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "databases.0.retention_policies.*", map[string]string{
+						"name":     "1week",
+						"duration": "168h0m0s",
+						"default":  "true",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestMatchFilters(t *testing.T) {
+	include := regexp.MustCompile("^telegraf")
+	exclude := regexp.MustCompile("_test$")
+
+	cases := []struct {
+		name             string
+		include, exclude *regexp.Regexp
+		expected         bool
+	}{
+		{"telegraf", nil, nil, true},
+		{"telegraf", include, nil, true},
+		{"_internal", include, nil, false},
+		{"telegraf_test", nil, exclude, false},
+		{"telegraf_test", include, exclude, false},
+		{"telegraf_prod", include, exclude, true},
+	}
+
+	for _, c := range cases {
+		if got := matchFilters(c.name, c.include, c.exclude); got != c.expected {
+			t.Errorf("matchFilters(%q, %v, %v) = %t, expected %t", c.name, c.include, c.exclude, got, c.expected)
+		}
+	}
+}
+
+func testAccDatabasesDataSourceConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "kept" {
+  name = "%[1]s-kept"
+
+  retention_policies {
+    name     = "1week"
+    duration = "1w"
+    default  = true
+  }
+}
+
+resource "influxdb_database" "excluded" {
+  name = "%[1]s-excluded"
+}
+
+data "influxdb_databases" "test" {
+  include                 = "^%[1]s-"
+  exclude                 = "-excluded$"
+  with_retention_policies = true
+
+  depends_on = [influxdb_database.kept, influxdb_database.excluded]
+}
+`, rName)
+}
@@ -0,0 +1,150 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ datasource.DataSource              = &fieldKeysDataSource{}
+	_ datasource.DataSourceWithConfigure = &fieldKeysDataSource{}
+)
+
+func newFieldKeysDataSource() datasource.DataSource {
+	return &fieldKeysDataSource{}
+}
+
+// fieldKeysDataSource lists the field keys and types of each measurement of a
+// database.
+type fieldKeysDataSource struct {
+	conn *connection
+}
+
+type fieldKeysDataSourceModel struct {
+	ID              types.String           `tfsdk:"id"`
+	Database        types.String           `tfsdk:"database"`
+	RetentionPolicy types.String           `tfsdk:"retention_policy"`
+	Measurement     types.String           `tfsdk:"measurement"`
+	Measurements    []measurementFieldKeys `tfsdk:"measurements"`
+	Timeouts        timeouts.Value         `tfsdk:"timeouts"`
+}
+
+type measurementFieldKeys struct {
+	Name   types.String `tfsdk:"name"`
+	Fields []fieldKey   `tfsdk:"fields"`
+}
+
+type fieldKey struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+func (d *fieldKeysDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_field_keys"
+}
+
+func (d *fieldKeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the field keys and types of each measurement of a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the database.",
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the measurements.",
+			},
+			"retention_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements having data in this retention policy.",
+			},
+			"measurement": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements whose name matches this regex.",
+			},
+			"measurements": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name": types.StringType,
+						"fields": types.ListType{
+							ElemType: types.ObjectType{
+								AttrTypes: map[string]attr.Type{
+									"name": types.StringType,
+									"type": types.StringType,
+								},
+							},
+						},
+					},
+				},
+				Description: "The measurements with their field keys and types.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *fieldKeysDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *fieldKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config fieldKeysDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compileFilter(config.Measurement, path.Root("measurement"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	database := config.Database.ValueString()
+	statement := fmt.Sprintf("SHOW FIELD KEYS ON %s%s", influxql.QuoteIdent(database), schemaSource(database, config.RetentionPolicy.ValueString(), config.Measurement.ValueString()))
+
+	series, err := showSchema(ctx, d.conn, database, statement)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading field keys", err.Error())
+		return
+	}
+
+	config.ID = config.Database
+	config.Measurements = []measurementFieldKeys{}
+	for _, row := range series {
+		measurement := measurementFieldKeys{
+			Name:   types.StringValue(row.Name),
+			Fields: []fieldKey{},
+		}
+		for _, result := range row.Values {
+			measurement.Fields = append(measurement.Fields, fieldKey{
+				Name: types.StringValue(result[0].(string)),
+				Type: types.StringValue(result[1].(string)),
+			})
+		}
+		config.Measurements = append(config.Measurements, measurement)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
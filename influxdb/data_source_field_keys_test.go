@@ -0,0 +1,40 @@
+package influxdb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBFieldKeysDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_field_keys.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(rName, `
+data "influxdb_field_keys" "test" {
+  database    = influxdb_points.test.database
+  measurement = "^mem$"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.name", "mem"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.fields.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "measurements.0.fields.*", map[string]string{
+						"name": "used",
+						"type": "integer",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "measurements.0.fields.*", map[string]string{
+						"name": "swapping",
+						"type": "boolean",
+					}),
+				),
+			},
+		},
+	})
+}
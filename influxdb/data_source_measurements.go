@@ -0,0 +1,191 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/influxdata/influxdb/models"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ datasource.DataSource              = &measurementsDataSource{}
+	_ datasource.DataSourceWithConfigure = &measurementsDataSource{}
+)
+
+func newMeasurementsDataSource() datasource.DataSource {
+	return &measurementsDataSource{}
+}
+
+// measurementsDataSource lists the measurements of a database.
+type measurementsDataSource struct {
+	conn *connection
+}
+
+type measurementsDataSourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Database        types.String   `tfsdk:"database"`
+	RetentionPolicy types.String   `tfsdk:"retention_policy"`
+	Measurement     types.String   `tfsdk:"measurement"`
+	Names           []types.String `tfsdk:"names"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (d *measurementsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_measurements"
+}
+
+func (d *measurementsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the measurements of a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the database.",
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the measurements.",
+			},
+			"retention_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements having data in this retention policy.",
+			},
+			"measurement": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements whose name matches this regex.",
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The names of the measurements.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *measurementsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *measurementsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config measurementsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compileFilter(config.Measurement, path.Root("measurement"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	names, err := showMeasurements(ctx, d.conn, config.Database.ValueString(), config.RetentionPolicy.ValueString(), config.Measurement.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading measurements", err.Error())
+		return
+	}
+
+	config.ID = config.Database
+	config.Names = []types.String{}
+	for _, name := range names {
+		config.Names = append(config.Names, types.StringValue(name))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
+
+// showMeasurements returns the names of the measurements of database,
+// restricted to a retention policy and a measurement regex when they are set.
+//
+// SHOW MEASUREMENTS reads the database index, which InfluxDB 1.8 does not
+// split by retention policy: the measurements of a single retention policy
+// are the series of SHOW FIELD KEYS on it instead, as every point has a field.
+func showMeasurements(ctx context.Context, conn *connection, database, retentionPolicy, measurement string) ([]string, error) {
+	if retentionPolicy != "" {
+		series, err := showSchema(ctx, conn, database, fmt.Sprintf("SHOW FIELD KEYS ON %s%s", influxql.QuoteIdent(database), schemaSource(database, retentionPolicy, measurement)))
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(series))
+		for _, row := range series {
+			names = append(names, row.Name)
+		}
+		return names, nil
+	}
+
+	statement := fmt.Sprintf("SHOW MEASUREMENTS ON %s", influxql.QuoteIdent(database))
+	if measurement != "" {
+		statement += " WITH MEASUREMENT =~ " + influxql.QuoteRegex(measurement)
+	}
+
+	series, err := showSchema(ctx, conn, database, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, row := range series {
+		for _, result := range row.Values {
+			names = append(names, result[0].(string))
+		}
+	}
+	return names, nil
+}
+
+// showSchema runs a SHOW statement exploring the schema of database and
+// returns its series, one per measurement.
+func showSchema(ctx context.Context, conn *connection, database, statement string) ([]models.Row, error) {
+	resp, err := query(ctx, conn, statement)
+	if isDatabaseNotFound(err) {
+		return nil, fmt.Errorf("database '%s' does not exist", database)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isDatabaseNotFound(resp.Results[0].Err) {
+		return nil, fmt.Errorf("database '%s' does not exist", database)
+	}
+	if resp.Results[0].Err != nil {
+		return nil, resp.Results[0].Err
+	}
+
+	return resp.Results[0].Series, nil
+}
+
+// schemaSource returns the FROM clause of the SHOW statements exploring the
+// schema of database, restricted to a retention policy and a measurement
+// regex when they are set.
+func schemaSource(database, retentionPolicy, measurement string) string {
+	if retentionPolicy == "" && measurement == "" {
+		return ""
+	}
+
+	if measurement == "" {
+		measurement = ".*"
+	}
+	if retentionPolicy == "" {
+		return " FROM " + influxql.QuoteRegex(measurement)
+	}
+	return " FROM " + influxql.QuoteIdents(database, retentionPolicy) + "." + influxql.QuoteRegex(measurement)
+}
@@ -0,0 +1,159 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBMeasurementsDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_measurements.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(rName, `
+data "influxdb_measurements" "test" {
+  database    = influxdb_points.test.database
+  measurement = "^cpu"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.0", "cpu"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.1", "cpu/idle"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBMeasurementsDataSource_retentionPolicy(t *testing.T) {
+	dataSourceName := "data.influxdb_measurements.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(rName, `
+resource "influxdb_retention_policy" "test" {
+  database = influxdb_database.test.name
+  name     = "1week"
+  duration = "1w"
+}
+
+resource "influxdb_points" "week" {
+  database         = influxdb_retention_policy.test.database
+  retention_policy = influxdb_retention_policy.test.name
+  # without a timestamp, as the point must fit in the retention policy
+  body             = "disk,host=a free=0.5"
+}
+
+data "influxdb_measurements" "test" {
+  database         = influxdb_points.week.database
+  retention_policy = influxdb_points.week.retention_policy
+}
+
+data "influxdb_measurements" "autogen" {
+  database         = influxdb_points.week.database
+  retention_policy = "autogen"
+  measurement      = "^disk|^mem"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.0", "disk"),
+					resource.TestCheckResourceAttr("data.influxdb_measurements.autogen", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.influxdb_measurements.autogen", "names.0", "mem"),
+				),
+			},
+		},
+	})
+}
+
+func TestShowMeasurements(t *testing.T) {
+	for _, tc := range []struct {
+		retentionPolicy string
+		measurement     string
+		statement       string
+		response        string
+	}{
+		{
+			measurement: "^cpu",
+			statement:   `SHOW MEASUREMENTS ON "telegraf" WITH MEASUREMENT =~ /^cpu/`,
+			response:    `{"results":[{"statement_id":0,"series":[{"name":"measurements","columns":["name"],"values":[["cpu"],["cpu/idle"]]}]}]}`,
+		},
+		{
+			retentionPolicy: "1week",
+			measurement:     "^cpu",
+			statement:       `SHOW FIELD KEYS ON "telegraf" FROM "telegraf"."1week"./^cpu/`,
+			response:        `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["fieldKey","fieldType"],"values":[["cores","integer"],["usage","float"]]},{"name":"cpu/idle","columns":["fieldKey","fieldType"],"values":[["value","float"]]}]}]}`,
+		},
+	} {
+		var statements []string
+		server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+			statements = append(statements, r.URL.Query().Get("q"))
+			w.Write([]byte(tc.response))
+		})
+
+		names, err := showMeasurements(context.Background(), testConnection(t, server.URL), "telegraf", tc.retentionPolicy, tc.measurement)
+		server.Close()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if len(statements) != 1 || statements[0] != tc.statement {
+			t.Errorf("expected %s, got %q", tc.statement, statements)
+		}
+		if expected := []string{"cpu", "cpu/idle"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %q, got %q", expected, names)
+		}
+	}
+}
+
+func TestSchemaSource(t *testing.T) {
+	for _, tc := range []struct {
+		retentionPolicy string
+		measurement     string
+		expected        string
+	}{
+		{},
+		{measurement: "^cpu", expected: ` FROM /^cpu/`},
+		{retentionPolicy: "1week", expected: ` FROM "telegraf"."1week"./.*/`},
+		{retentionPolicy: "1week", measurement: "^cpu/idle$", expected: ` FROM "telegraf"."1week"./^cpu\/idle$/`},
+	} {
+		if actual := schemaSource("telegraf", tc.retentionPolicy, tc.measurement); actual != tc.expected {
+			t.Errorf("schemaSource(%q, %q): expected %q, got %q", tc.retentionPolicy, tc.measurement, tc.expected, actual)
+		}
+	}
+}
+
+// testAccSchemaDataSourceConfig writes points to a new database before
+// reading its schema with dataSource.
+func testAccSchemaDataSourceConfig(rName, dataSource string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %q
+}
+
+resource "influxdb_points" "test" {
+  database  = influxdb_database.test.name
+  precision = "s"
+  body      = <<-EOT
+    cpu,host=a,region=eu usage=0.5,cores=4i 1700000000
+    cpu,host=b,region=us usage=0.25,cores=8i 1700000000
+    cpu/idle,host=a value=1 1700000000
+    mem,host=c used=1024i,swapping=false 1700000000
+  EOT
+}
+%s`, rName, dataSource)
+}
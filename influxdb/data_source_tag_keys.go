@@ -0,0 +1,134 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ datasource.DataSource              = &tagKeysDataSource{}
+	_ datasource.DataSourceWithConfigure = &tagKeysDataSource{}
+)
+
+func newTagKeysDataSource() datasource.DataSource {
+	return &tagKeysDataSource{}
+}
+
+// tagKeysDataSource lists the tag keys of each measurement of a database.
+type tagKeysDataSource struct {
+	conn *connection
+}
+
+type tagKeysDataSourceModel struct {
+	ID              types.String         `tfsdk:"id"`
+	Database        types.String         `tfsdk:"database"`
+	RetentionPolicy types.String         `tfsdk:"retention_policy"`
+	Measurement     types.String         `tfsdk:"measurement"`
+	Measurements    []measurementTagKeys `tfsdk:"measurements"`
+	Timeouts        timeouts.Value       `tfsdk:"timeouts"`
+}
+
+type measurementTagKeys struct {
+	Name    types.String   `tfsdk:"name"`
+	TagKeys []types.String `tfsdk:"tag_keys"`
+}
+
+func (d *tagKeysDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag_keys"
+}
+
+func (d *tagKeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the tag keys of each measurement of a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the database.",
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the measurements.",
+			},
+			"retention_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements having data in this retention policy.",
+			},
+			"measurement": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements whose name matches this regex.",
+			},
+			"measurements": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":     types.StringType,
+						"tag_keys": types.ListType{ElemType: types.StringType},
+					},
+				},
+				Description: "The measurements with their tag keys.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *tagKeysDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *tagKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config tagKeysDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compileFilter(config.Measurement, path.Root("measurement"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	database := config.Database.ValueString()
+	statement := fmt.Sprintf("SHOW TAG KEYS ON %s%s", influxql.QuoteIdent(database), schemaSource(database, config.RetentionPolicy.ValueString(), config.Measurement.ValueString()))
+
+	series, err := showSchema(ctx, d.conn, database, statement)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading tag keys", err.Error())
+		return
+	}
+
+	config.ID = config.Database
+	config.Measurements = []measurementTagKeys{}
+	for _, row := range series {
+		measurement := measurementTagKeys{
+			Name:    types.StringValue(row.Name),
+			TagKeys: []types.String{},
+		}
+		for _, result := range row.Values {
+			measurement.TagKeys = append(measurement.TagKeys, types.StringValue(result[0].(string)))
+		}
+		config.Measurements = append(config.Measurements, measurement)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
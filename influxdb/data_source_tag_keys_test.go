@@ -0,0 +1,35 @@
+package influxdb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBTagKeysDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_tag_keys.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(rName, `
+data "influxdb_tag_keys" "test" {
+  database         = influxdb_points.test.database
+  retention_policy = "autogen"
+  measurement      = "^cpu$"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.name", "cpu"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.tag_keys.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.tag_keys.0", "host"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.0.tag_keys.1", "region"),
+				),
+			},
+		},
+	})
+}
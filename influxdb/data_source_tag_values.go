@@ -0,0 +1,162 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ datasource.DataSource              = &tagValuesDataSource{}
+	_ datasource.DataSourceWithConfigure = &tagValuesDataSource{}
+)
+
+func newTagValuesDataSource() datasource.DataSource {
+	return &tagValuesDataSource{}
+}
+
+// tagValuesDataSource lists the values of a tag key in each measurement of a
+// database.
+type tagValuesDataSource struct {
+	conn *connection
+}
+
+type tagValuesDataSourceModel struct {
+	ID              types.String           `tfsdk:"id"`
+	Database        types.String           `tfsdk:"database"`
+	RetentionPolicy types.String           `tfsdk:"retention_policy"`
+	Measurement     types.String           `tfsdk:"measurement"`
+	Key             types.String           `tfsdk:"key"`
+	Values          []types.String         `tfsdk:"values"`
+	Measurements    []measurementTagValues `tfsdk:"measurements"`
+	Timeouts        timeouts.Value         `tfsdk:"timeouts"`
+}
+
+type measurementTagValues struct {
+	Name   types.String   `tfsdk:"name"`
+	Values []types.String `tfsdk:"values"`
+}
+
+func (d *tagValuesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag_values"
+}
+
+func (d *tagValuesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the values of a tag key in each measurement of a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The database and tag key, as database/key.",
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the measurements.",
+			},
+			"retention_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements having data in this retention policy.",
+			},
+			"measurement": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the measurements whose name matches this regex.",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "The tag key whose values are listed.",
+			},
+			"values": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The sorted values of the tag key in all the listed measurements.",
+			},
+			"measurements": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":   types.StringType,
+						"values": types.ListType{ElemType: types.StringType},
+					},
+				},
+				Description: "The measurements having the tag key, with its values.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *tagValuesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *tagValuesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config tagValuesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compileFilter(config.Measurement, path.Root("measurement"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	database := config.Database.ValueString()
+	key := config.Key.ValueString()
+	statement := fmt.Sprintf("SHOW TAG VALUES ON %s%s WITH KEY = %s", influxql.QuoteIdent(database), schemaSource(database, config.RetentionPolicy.ValueString(), config.Measurement.ValueString()), influxql.QuoteIdent(key))
+
+	series, err := showSchema(ctx, d.conn, database, statement)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading tag values", err.Error())
+		return
+	}
+
+	config.ID = types.StringValue(fmt.Sprintf("%s/%s", database, key))
+	config.Measurements = []measurementTagValues{}
+
+	values := map[string]bool{}
+	for _, row := range series {
+		measurement := measurementTagValues{
+			Name:   types.StringValue(row.Name),
+			Values: []types.String{},
+		}
+		for _, result := range row.Values {
+			measurement.Values = append(measurement.Values, types.StringValue(result[1].(string)))
+			values[result[1].(string)] = true
+		}
+		config.Measurements = append(config.Measurements, measurement)
+	}
+
+	sorted := make([]string, 0, len(values))
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	sort.Strings(sorted)
+
+	config.Values = make([]types.String, len(sorted))
+	for i, value := range sorted {
+		config.Values[i] = types.StringValue(value)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
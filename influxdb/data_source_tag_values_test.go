@@ -0,0 +1,35 @@
+package influxdb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBTagValuesDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_tag_values.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(rName, `
+data "influxdb_tag_values" "test" {
+  database = influxdb_points.test.database
+  key      = "host"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", rName+"/host"),
+					resource.TestCheckResourceAttr(dataSourceName, "measurements.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "values.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "values.0", "a"),
+					resource.TestCheckResourceAttr(dataSourceName, "values.1", "b"),
+					resource.TestCheckResourceAttr(dataSourceName, "values.2", "c"),
+				),
+			},
+		},
+	})
+}
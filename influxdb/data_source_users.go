@@ -0,0 +1,163 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &usersDataSource{}
+	_ datasource.DataSourceWithConfigure = &usersDataSource{}
+)
+
+func newUsersDataSource() datasource.DataSource {
+	return &usersDataSource{}
+}
+
+// usersDataSource lists the users with their admin flag and grants.
+type usersDataSource struct {
+	conn *connection
+}
+
+type usersDataSourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	Include  types.String   `tfsdk:"include"`
+	Exclude  types.String   `tfsdk:"exclude"`
+	Names    []types.String `tfsdk:"names"`
+	Users    []userModel    `tfsdk:"users"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type userModel struct {
+	Name   types.String `tfsdk:"name"`
+	Admin  types.Bool   `tfsdk:"admin"`
+	Grants []grantModel `tfsdk:"grants"`
+}
+
+type grantModel struct {
+	Database  types.String `tfsdk:"database"`
+	Privilege types.String `tfsdk:"privilege"`
+}
+
+func (d *usersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *usersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the users of the server with their admin flag and grants.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Always users.",
+			},
+			"include": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the users whose name matches this regex.",
+			},
+			"exclude": schema.StringAttribute{
+				Optional:    true,
+				Description: "Do not list the users whose name matches this regex.",
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The names of the users.",
+			},
+			"users": schema.ListAttribute{
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":  types.StringType,
+						"admin": types.BoolType,
+						"grants": types.ListType{
+							ElemType: types.ObjectType{
+								AttrTypes: map[string]attr.Type{
+									"database":  types.StringType,
+									"privilege": types.StringType,
+								},
+							},
+						},
+					},
+				},
+				Description: "The users, with their admin flag and their privileges on each database.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *usersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config usersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	include := compileFilter(config.Include, path.Root("include"), &resp.Diagnostics)
+	exclude := compileFilter(config.Exclude, path.Root("exclude"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	users, err := showUsers(ctx, d.conn)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading users", err.Error())
+		return
+	}
+
+	config.ID = types.StringValue("users")
+	config.Names = []types.String{}
+	config.Users = []userModel{}
+	for _, u := range users {
+		name := u["name"].(string)
+		if !matchFilters(name, include, exclude) {
+			continue
+		}
+
+		grants, err := showGrants(ctx, d.conn, name)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading grants", err.Error())
+			return
+		}
+
+		user := userModel{
+			Name:   types.StringValue(name),
+			Admin:  types.BoolValue(u["admin"].(bool)),
+			Grants: []grantModel{},
+		}
+		for _, grant := range grants {
+			user.Grants = append(user.Grants, grantModel{
+				Database:  types.StringValue(grant["database"]),
+				Privilege: types.StringValue(grant["privilege"]),
+			})
+		}
+
+		config.Names = append(config.Names, user.Name)
+		config.Users = append(config.Users, user)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
@@ -0,0 +1,74 @@
+package influxdb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInfluxDBUsersDataSource_basic(t *testing.T) {
+	dataSourceName := "data.influxdb_users.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUsersDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "users.*", map[string]string{
+						"name":     rName + "-admin",
+						"admin":    "true",
+						"grants.#": "0",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "users.*", map[string]string{
+						"name":               rName + "-reader",
+						"admin":              "false",
+						"grants.#":           "1",
+						"grants.0.database":  rName,
+						"grants.0.privilege": "READ",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccUsersDataSourceConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_user" "admin" {
+  name     = "%[1]s-admin"
+  password = %[1]q
+  admin    = true
+}
+
+resource "influxdb_user" "reader" {
+  name     = "%[1]s-reader"
+  password = %[1]q
+
+  grant {
+    database  = influxdb_database.test.name
+    privilege = "READ"
+  }
+}
+
+resource "influxdb_user" "excluded" {
+  name     = "%[1]s-excluded"
+  password = %[1]q
+}
+
+data "influxdb_users" "test" {
+  include = "^%[1]s-"
+  exclude = "-excluded$"
+
+  depends_on = [influxdb_user.admin, influxdb_user.reader, influxdb_user.excluded]
+}
+`, rName)
+}
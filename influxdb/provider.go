@@ -1,13 +1,43 @@
 package influxdb
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/influxdata/influxdb/client"
 )
 
+// ProtoV5ProviderServerFactory returns a muxed provider server serving both the
+// terraform-plugin-sdk/v2 resources of Provider and the terraform-plugin-framework
+// resources of the framework provider. The framework provider shares the
+// connection configured by the SDK provider, which is configured first.
+func ProtoV5ProviderServerFactory(ctx context.Context, version string) (func() tfprotov5.ProviderServer, error) {
+	return protoV5ProviderServerFactory(ctx, Provider(), version)
+}
+
+func protoV5ProviderServerFactory(ctx context.Context, primary *schema.Provider, version string) (func() tfprotov5.ProviderServer, error) {
+	servers := []func() tfprotov5.ProviderServer{
+		primary.GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider(primary, version)),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
@@ -25,6 +55,21 @@ func Provider() *schema.Provider {
 					"INFLUXDB_URL", "http://localhost:8086/",
 				),
 			},
+			"endpoints": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Influxdb connection urls of identical servers, replacing url",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"endpoints_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "How endpoints are used: failover to the first healthy one, or broadcast mutating statements to all",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_ENDPOINTS_MODE", endpointsFailover),
+				ValidateFunc: validation.StringInSlice([]string{endpointsFailover, endpointsBroadcast}, false),
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -39,59 +84,237 @@ func Provider() *schema.Provider {
 				StateFunc:   hashSum,
 				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_PASSWORD", ""),
 			},
+			"shared_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Influxdb shared secret used to sign JWT tokens instead of sending the password",
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_SHARED_SECRET", ""),
+			},
+			"token_lifetime": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Lifetime of the JWT tokens signed with the shared secret",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_TOKEN_LIFETIME", "1m"),
+				ValidateFunc: validateDuration,
+			},
 			"skip_ssl_verify": {
 				Type:        schema.TypeBool,
 				Description: "skip ssl verify on connection",
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_SKIP_SSL_VERIFY", "0"),
 			},
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded CA bundle used to verify the server certificate, or the path to it",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_CA_CERTIFICATE", ""),
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded client certificate for mutual TLS, or the path to it",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_CLIENT_CERTIFICATE", ""),
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded client private key for mutual TLS, or the path to it",
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_CLIENT_KEY", ""),
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Server name used to verify the server certificate",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_TLS_SERVER_NAME", ""),
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "URL of the HTTP proxy used to reach the server",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_PROXY_URL", ""),
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Additional HTTP headers sent with every request",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_agent": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "User agent sent with every request",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_USER_AGENT", "InfluxDBClient"),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of retries on connection errors and 5xx responses",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_MAX_RETRIES", 3),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_min_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Delay before the first retry, doubled on each following retry",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_RETRY_MIN_BACKOFF", "1s"),
+				ValidateFunc: validateDuration,
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Maximum delay between two retries",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_RETRY_MAX_BACKOFF", "30s"),
+				ValidateFunc: validateDuration,
+			},
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Record mutating statements instead of executing them, reads are still performed",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_DRY_RUN", false),
+			},
+			"statement_log_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File where mutating statements are appended, with passwords redacted",
+				DefaultFunc: schema.EnvDefaultFunc("INFLUXDB_STATEMENT_LOG_FILE", ""),
+			},
+			"wait_for_ready": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "How long to wait for the server to be ready before running the first statement",
+				DefaultFunc:  schema.EnvDefaultFunc("INFLUXDB_WAIT_FOR_READY", "0s"),
+				ValidateFunc: validateDuration,
+			},
 		},
 
-		ConfigureFunc: configure,
+		ConfigureContextFunc: configure,
 	}
 }
 
-func configure(d *schema.ResourceData) (interface{}, error) {
-	url, err := url.Parse(d.Get("url").(string))
-	if err != nil {
-		return nil, fmt.Errorf("invalid InfluxDB URL: %w", err)
+func configure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	// endpoints take precedence over url
+	endpoints := []string{d.Get("url").(string)}
+	if v := d.Get("endpoints").([]interface{}); len(v) > 0 {
+		endpoints = make([]string, len(v))
+		for i, endpoint := range v {
+			endpoints[i] = endpoint.(string)
+		}
 	}
 
-	config := client.Config{
-		URL:       *url,
-		Username:  d.Get("username").(string),
-		Password:  d.Get("password").(string),
-		UnsafeSsl: d.Get("skip_ssl_verify").(bool),
+	username := d.Get("username").(string)
+	sharedSecret := d.Get("shared_secret").(string)
+	if sharedSecret != "" && username == "" {
+		return nil, diag.Errorf("username is required to sign tokens with shared_secret")
 	}
 
-	conn, err := client.NewClient(config)
+	tlsConfig, err := newTLSConfig(tlsOptions{
+		SkipVerify:        d.Get("skip_ssl_verify").(bool),
+		CACertificate:     d.Get("ca_certificate").(string),
+		ClientCertificate: d.Get("client_certificate").(string),
+		ClientKey:         d.Get("client_key").(string),
+		ServerName:        d.Get("tls_server_name").(string),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error connecting server: %w", err)
+		return nil, diag.Errorf("invalid TLS configuration: %s", err)
 	}
 
-	// assume that an InfluxBD is already provision when using the InfluxDB provider.
-	// you have to manage dependency between your modules
-	_, version, err := conn.Ping()
-	if err != nil {
-		return nil, fmt.Errorf("error connecting server: %w", err)
+	var proxyURL *url.URL
+	if v := d.Get("proxy_url").(string); v != "" {
+		proxyURL, err = url.Parse(v)
+		if err != nil {
+			return nil, diag.Errorf("invalid proxy URL: %s", err)
+		}
+	}
+
+	headers := map[string]string{}
+	for name, value := range d.Get("headers").(map[string]interface{}) {
+		headers[name] = value.(string)
 	}
 
-	if version == "" {
-		return nil, fmt.Errorf("error connecting server: no version information %s", version)
+	// durations are checked by validateDuration
+	tokenLifetime, _ := time.ParseDuration(d.Get("token_lifetime").(string))
+	retryMinBackoff, _ := time.ParseDuration(d.Get("retry_min_backoff").(string))
+	retryMaxBackoff, _ := time.ParseDuration(d.Get("retry_max_backoff").(string))
+	waitForReady, _ := time.ParseDuration(d.Get("wait_for_ready").(string))
+	if retryMinBackoff > retryMaxBackoff {
+		return nil, diag.Errorf("retry_min_backoff (%s) must not exceed retry_max_backoff (%s)", retryMinBackoff, retryMaxBackoff)
+	}
+
+	config := connectionConfig{
+		Endpoints:       endpoints,
+		EndpointsMode:   d.Get("endpoints_mode").(string),
+		Username:        username,
+		Password:        d.Get("password").(string),
+		SharedSecret:    sharedSecret,
+		TokenLifetime:   tokenLifetime,
+		TLS:             tlsConfig,
+		ProxyURL:        proxyURL,
+		Headers:         headers,
+		UserAgent:       d.Get("user_agent").(string),
+		MaxRetries:      d.Get("max_retries").(int),
+		RetryMinBackoff: retryMinBackoff,
+		RetryMaxBackoff: retryMaxBackoff,
+		WaitForReady:    waitForReady,
+		DryRun:          d.Get("dry_run").(bool),
+		StatementLog:    d.Get("statement_log_file").(string),
+	}
+
+	// the server is not contacted yet: it may be created in the same apply,
+	// the connection waits for it before running the first statement.
+	conn, err := newConnection(config)
+	if err != nil {
+		return nil, diag.FromErr(err)
 	}
 
 	return conn, nil
 }
 
-func exec(conn *client.Client, query string) error {
-	resp, err := conn.Query(client.Query{
-		Command: query,
+// query runs a read-only statement and returns the server response. The
+// request is cancelled when ctx expires, in which case the error names the statement.
+func query(ctx context.Context, conn *connection, command string) (*client.Response, error) {
+	resp, err := conn.QueryContext(ctx, client.Query{
+		Command: command,
 	})
 	if err != nil {
-		return err
+		return nil, statementError(ctx, command, err)
 	}
 	if resp.Err != nil {
-		return resp.Err
+		return nil, resp.Err
+	}
+	return resp, nil
+}
+
+// exec runs a mutating statement whose result is not used.
+func exec(ctx context.Context, conn *connection, command string) error {
+	resp, err := conn.ExecContext(ctx, client.Query{
+		Command: command,
+	})
+	if err != nil {
+		return statementError(ctx, command, err)
+	}
+	return resp.Err
+}
+
+// execOn runs a mutating statement in the context of a database, for
+// statements such as DROP SERIES which have no ON clause.
+func execOn(ctx context.Context, conn *connection, database, command string) error {
+	resp, err := conn.ExecContext(ctx, client.Query{
+		Command:  command,
+		Database: database,
+	})
+	if err != nil {
+		return statementError(ctx, command, err)
+	}
+	return resp.Err
+}
+
+func statementError(ctx context.Context, command string, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timeout while executing %q: %w", redactStatement(command), ctx.Err())
 	}
-	return nil
+	return err
 }
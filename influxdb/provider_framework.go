@@ -0,0 +1,198 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// metaProvider is implemented by the terraform-plugin-sdk/v2 provider, which
+// owns the provider configuration and the InfluxDB connection.
+type metaProvider interface {
+	Meta() interface{}
+}
+
+var _ provider.Provider = &frameworkProvider{}
+
+// frameworkProvider serves the resources and data sources written with
+// terraform-plugin-framework. It is muxed with the SDK provider returned by
+// Provider, so its schema must stay identical to the SDK provider schema.
+type frameworkProvider struct {
+	primary metaProvider
+	version string
+}
+
+// NewFrameworkProvider returns a terraform-plugin-framework provider which
+// reuses the connection configured by primary.
+func NewFrameworkProvider(primary metaProvider, version string) provider.Provider {
+	return &frameworkProvider{
+		primary: primary,
+		version: version,
+	}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "influxdb"
+	resp.Version = p.version
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				Optional:    true,
+				Description: "Influxdb connection url",
+			},
+			"endpoints": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Influxdb connection urls of identical servers, replacing url",
+			},
+			"endpoints_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How endpoints are used: failover to the first healthy one, or broadcast mutating statements to all",
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "Influxdb user name",
+			},
+			"password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Influxdb password",
+			},
+			"shared_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Influxdb shared secret used to sign JWT tokens instead of sending the password",
+			},
+			"token_lifetime": schema.StringAttribute{
+				Optional:    true,
+				Description: "Lifetime of the JWT tokens signed with the shared secret",
+			},
+			"skip_ssl_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "skip ssl verify on connection",
+			},
+			"ca_certificate": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA bundle used to verify the server certificate, or the path to it",
+			},
+			"client_certificate": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate for mutual TLS, or the path to it",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client private key for mutual TLS, or the path to it",
+			},
+			"tls_server_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Server name used to verify the server certificate",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the HTTP proxy used to reach the server",
+			},
+			"headers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Additional HTTP headers sent with every request",
+			},
+			"user_agent": schema.StringAttribute{
+				Optional:    true,
+				Description: "User agent sent with every request",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of retries on connection errors and 5xx responses",
+			},
+			"retry_min_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: "Delay before the first retry, doubled on each following retry",
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum delay between two retries",
+			},
+			"dry_run": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Record mutating statements instead of executing them, reads are still performed",
+			},
+			"statement_log_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "File where mutating statements are appended, with passwords redacted",
+			},
+			"wait_for_ready": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long to wait for the server to be ready before running the first statement",
+			},
+		},
+	}
+}
+
+// Configure does not read the configuration: the SDK provider is configured
+// first by the mux server and its connection is shared with framework resources.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	conn, ok := p.primary.Meta().(*connection)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider meta",
+			fmt.Sprintf("expected *connection, got: %T", p.primary.Meta()),
+		)
+		return
+	}
+
+	resp.ResourceData = conn
+	resp.DataSourceData = conn
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newRetentionPolicyResource,
+		newGrantResource,
+		newSubscriptionResource,
+		newPointsResource,
+		newSeriesCleanupResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newContinuousQueriesDataSource,
+		newDatabaseDataSource,
+		newDatabasesDataSource,
+		newFieldKeysDataSource,
+		newMeasurementsDataSource,
+		newTagKeysDataSource,
+		newTagValuesDataSource,
+		newUsersDataSource,
+	}
+}
+
+// providerConnection returns the connection shared by the provider with the
+// framework resources and data sources. The provider data is nil when the
+// provider is not configured yet, during validation.
+func providerConnection(providerData interface{}, diags *diag.Diagnostics) *connection {
+	if providerData == nil {
+		return nil
+	}
+
+	conn, ok := providerData.(*connection)
+	if !ok {
+		diags.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("expected *connection, got: %T", providerData),
+		)
+		return nil
+	}
+
+	return conn
+}
@@ -1,25 +1,36 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"encoding/json"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/influxdata/influxdb/client"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
 )
 
 func resourceDatabase() *schema.Resource {
 	return &schema.Resource{
-		Create: createDatabase,
-		Read:   readDatabase,
-		Delete: deleteDatabase,
-		Update: updateDatabase,
+		CreateContext: createDatabase,
+		ReadContext:   readDatabase,
+		DeleteContext: deleteDatabase,
+		UpdateContext: updateDatabase,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -62,21 +73,12 @@ func resourceDatabase() *schema.Resource {
 	}
 }
 
-func createDatabase(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func createDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 
 	name := d.Get("name").(string)
-	queryStr := fmt.Sprintf("CREATE DATABASE %q", name)
-	query := client.Query{
-		Command: queryStr,
-	}
-
-	resp, err := conn.Query(query)
-	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+	if err := exec(ctx, conn, fmt.Sprintf("CREATE DATABASE %s", influxql.QuoteIdent(name))); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(name)
@@ -85,72 +87,85 @@ func createDatabase(d *schema.ResourceData, meta interface{}) error {
 		retentionPolicies := v.(*schema.Set).List()
 		for _, vv := range retentionPolicies {
 			retentionPolicy := vv.(map[string]interface{})
-			if err := createRetentionPolicy(conn, retentionPolicy["name"].(string), retentionPolicy["duration"].(string), retentionPolicy["replication"].(int), retentionPolicy["shardgroupduration"].(string), retentionPolicy["default"].(bool), name); err != nil {
-				return err
+			if err := createRetentionPolicy(ctx, conn, retentionPolicy["name"].(string), retentionPolicy["duration"].(string), retentionPolicy["replication"].(int), retentionPolicy["shardgroupduration"].(string), retentionPolicy["default"].(bool), name); err != nil {
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	return readDatabase(d, meta)
-}
+	// statements were only recorded, there is nothing to read back
+	if conn.config.DryRun {
+		return nil
+	}
 
-func createRetentionPolicy(conn *client.Client, policyName string, duration string, replication int, shardGroupDuration string, defaultPolicy bool, database string) error {
-	var shardDuration string
+	return readDatabase(ctx, d, meta)
+}
 
-	if shardGroupDuration != "" {
-		shardDuration = fmt.Sprintf("SHARD DURATION %s ", shardGroupDuration)
+func createRetentionPolicy(ctx context.Context, conn *connection, policyName string, duration string, replication int, shardGroupDuration string, defaultPolicy bool, database string) error {
+	clauses, err := retentionPolicyClauses(duration, replication, shardGroupDuration, defaultPolicy)
+	if err != nil {
+		return err
 	}
 
-	if defaultPolicy {
-		return exec(conn, fmt.Sprintf("CREATE RETENTION POLICY %q ON %q DURATION %s REPLICATION %d %s DEFAULT", policyName, database, duration, replication, shardDuration))
-	} else {
-		return exec(conn, fmt.Sprintf("CREATE RETENTION POLICY %q ON %q DURATION %s REPLICATION %d %s", policyName, database, duration, replication, shardDuration))
+	return exec(ctx, conn, fmt.Sprintf("CREATE RETENTION POLICY %s ON %s %s", influxql.QuoteIdent(policyName), influxql.QuoteIdent(database), clauses))
+}
+
+func updateRetentionPolicy(ctx context.Context, conn *connection, policyName string, duration string, replication int, shardGroupDuration string, defaultPolicy bool, database string) error {
+	clauses, err := retentionPolicyClauses(duration, replication, shardGroupDuration, defaultPolicy)
+	if err != nil {
+		return err
 	}
+
+	return exec(ctx, conn, fmt.Sprintf("ALTER RETENTION POLICY %s ON %s %s", influxql.QuoteIdent(policyName), influxql.QuoteIdent(database), clauses))
 }
 
-func updateRetentionPolicy(conn *client.Client, policyName string, duration string, replication int, shardGroupDuration string, defaultPolicy bool, database string) error {
-	var shardDuration string
+// retentionPolicyClauses returns the DURATION, REPLICATION, SHARD DURATION and
+// DEFAULT clauses shared by CREATE and ALTER RETENTION POLICY.
+func retentionPolicyClauses(duration string, replication int, shardGroupDuration string, defaultPolicy bool) (string, error) {
+	duration, err := influxql.Duration(duration)
+	if err != nil {
+		return "", fmt.Errorf("invalid retention policy duration: %w", err)
+	}
+
+	clauses := fmt.Sprintf("DURATION %s REPLICATION %d", duration, replication)
 
 	if shardGroupDuration != "" {
-		shardDuration = fmt.Sprintf("SHARD DURATION %s ", shardGroupDuration)
+		shardGroupDuration, err := influxql.Duration(shardGroupDuration)
+		if err != nil {
+			return "", fmt.Errorf("invalid shard group duration: %w", err)
+		}
+		clauses += fmt.Sprintf(" SHARD DURATION %s", shardGroupDuration)
 	}
 
 	if defaultPolicy {
-		return exec(conn, fmt.Sprintf("ALTER RETENTION POLICY %q ON %q DURATION %s REPLICATION %d %s DEFAULT", policyName, database, duration, replication, shardDuration))
-	} else {
-		return exec(conn, fmt.Sprintf("ALTER RETENTION POLICY %q ON %q DURATION %s REPLICATION %d %s", policyName, database, duration, replication, shardDuration))
+		clauses += " DEFAULT"
 	}
+
+	return clauses, nil
 }
 
-func deleteRetentionPolicy(conn *client.Client, policyName string, database string) error {
-	return exec(conn, fmt.Sprintf("DROP RETENTION POLICY %q ON %q", policyName, database))
+func deleteRetentionPolicy(ctx context.Context, conn *connection, policyName string, database string) error {
+	return exec(ctx, conn, fmt.Sprintf("DROP RETENTION POLICY %s ON %s", influxql.QuoteIdent(policyName), influxql.QuoteIdent(database)))
 }
 
-func readDatabase(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func readDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 	name := d.Id()
 
 	// InfluxDB doesn't have a command to check the existence of a single
 	// database, so we instead must read the list of all databases and see
 	// if ours is present in it.
-	query := client.Query{
-		Command: "SHOW DATABASES",
-	}
-
-	resp, err := conn.Query(query)
+	databases, err := showDatabases(ctx, conn)
 	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+		return diag.FromErr(err)
 	}
 
-	for _, result := range resp.Results[0].Series[0].Values {
-		if result[0] == name {
+	for _, database := range databases {
+		if database == name {
 			d.Set("name", d.Id())
-			err := readRetentionPolicies(d, meta)
+			err := readRetentionPolicies(ctx, d, meta)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			return nil
 		}
@@ -162,23 +177,32 @@ func readDatabase(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func readRetentionPolicies(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
-	name := d.Id()
+// showDatabases returns the names of all databases.
+func showDatabases(ctx context.Context, conn *connection) ([]string, error) {
+	resp, err := query(ctx, conn, "SHOW DATABASES")
+	if err != nil {
+		return nil, err
+	}
 
-	query := client.Query{
-		Command: fmt.Sprintf("SHOW RETENTION POLICIES ON %q", name),
+	databases := []string{}
+	for _, series := range resp.Results[0].Series {
+		for _, result := range series.Values {
+			databases = append(databases, result[0].(string))
+		}
 	}
 
-	resp, err := conn.Query(query)
+	return databases, nil
+}
+
+func readRetentionPolicies(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*connection)
+	name := d.Id()
+
+	policies, err := showRetentionPolicies(ctx, conn, name)
 	if err != nil {
 		return err
 	}
 
-	if resp.Err != nil {
-		return resp.Err
-	}
-
 	defaultRetentionPolicy := map[string]interface{}{
 		"name":               "autogen",
 		"duration":           "0s",
@@ -189,12 +213,38 @@ func readRetentionPolicies(d *schema.ResourceData, meta interface{}) error {
 
 	retentionPolicies := []interface{}{}
 
-	if resp.Results[0].Err == nil {
-		for _, result := range resp.Results[0].Series[0].Values {
+	for _, retentionPolicy := range policies {
+		if reflect.DeepEqual(retentionPolicy, defaultRetentionPolicy) {
+			continue
+		}
+
+		retentionPolicies = append(retentionPolicies, retentionPolicy)
+	}
+
+	d.Set("retention_policies", retentionPolicies)
+	return nil
+}
+
+// showRetentionPolicies returns the retention policies of a database, in the
+// format of the retention_policies elements.
+func showRetentionPolicies(ctx context.Context, conn *connection, database string) ([]map[string]interface{}, error) {
+	resp, err := query(ctx, conn, fmt.Sprintf("SHOW RETENTION POLICIES ON %s", influxql.QuoteIdent(database)))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Results[0].Err != nil {
+		return nil, resp.Results[0].Err
+	}
+
+	retentionPolicies := []map[string]interface{}{}
+
+	for _, series := range resp.Results[0].Series {
+		for _, result := range series.Values {
 
 			replication, err := result[3].(json.Number).Int64()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			retentionPolicy := map[string]interface{}{
@@ -205,40 +255,32 @@ func readRetentionPolicies(d *schema.ResourceData, meta interface{}) error {
 				"default":            result[4].(bool),
 			}
 
-			if reflect.DeepEqual(retentionPolicy, defaultRetentionPolicy) {
-				continue
-			}
-
 			retentionPolicies = append(retentionPolicies, retentionPolicy)
 		}
 	}
 
-	d.Set("retention_policies", retentionPolicies)
-	return nil
+	return retentionPolicies, nil
 }
 
-func deleteDatabase(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
-	name := d.Id()
+// isDatabaseNotFound reports whether err is the error returned by statements
+// on a missing database.
+func isDatabaseNotFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "database not found")
+}
 
-	queryStr := fmt.Sprintf("DROP DATABASE %q", name)
-	query := client.Query{
-		Command: queryStr,
-	}
+func deleteDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
+	name := d.Id()
 
-	resp, err := conn.Query(query)
-	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+	if err := exec(ctx, conn, fmt.Sprintf("DROP DATABASE %s", influxql.QuoteIdent(name))); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func updateDatabase(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func updateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 	name := d.Get("name").(string)
 
 	if d.HasChange("retention_policies") {
@@ -262,8 +304,8 @@ func updateDatabase(d *schema.ResourceData, meta interface{}) error {
 			oldRPMap[policyName] = true
 
 			if !newRPMap[policyName] {
-				if err := deleteRetentionPolicy(conn, policyName, name); err != nil {
-					return err
+				if err := deleteRetentionPolicy(ctx, conn, policyName, name); err != nil {
+					return diag.FromErr(err)
 				}
 			}
 		}
@@ -274,16 +316,21 @@ func updateDatabase(d *schema.ResourceData, meta interface{}) error {
 
 			// If policy is not in old map, it has to be created newly, otherwise it has to be updated
 			if !oldRPMap[policyName] {
-				if err := createRetentionPolicy(conn, policyName, newPolicy["duration"].(string), newPolicy["replication"].(int), newPolicy["shardgroupduration"].(string), newPolicy["default"].(bool), name); err != nil {
-					return err
+				if err := createRetentionPolicy(ctx, conn, policyName, newPolicy["duration"].(string), newPolicy["replication"].(int), newPolicy["shardgroupduration"].(string), newPolicy["default"].(bool), name); err != nil {
+					return diag.FromErr(err)
 				}
 			} else {
-				if err := updateRetentionPolicy(conn, policyName, newPolicy["duration"].(string), newPolicy["replication"].(int), newPolicy["shardgroupduration"].(string), newPolicy["default"].(bool), name); err != nil {
-					return err
+				if err := updateRetentionPolicy(ctx, conn, policyName, newPolicy["duration"].(string), newPolicy["replication"].(int), newPolicy["shardgroupduration"].(string), newPolicy["default"].(bool), name); err != nil {
+					return diag.FromErr(err)
 				}
 			}
 		}
 	}
 
-	return readDatabase(d, meta)
+	// statements were only recorded, there is nothing to read back
+	if conn.config.DryRun {
+		return nil
+	}
+
+	return readDatabase(ctx, d, meta)
 }
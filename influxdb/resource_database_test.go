@@ -1,12 +1,13 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/influxdata/influxdb/client"
 )
 
@@ -15,7 +16,7 @@ func TestAccInfluxDBDatabase_basic(t *testing.T) {
 
 	resourceName := "influxdb_database.test"
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccDatabaseConfig(rName),
@@ -39,7 +40,7 @@ func TestAccInfluxDBDatabase_retention(t *testing.T) {
 
 	resourceName := "influxdb_database.test"
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccDatabaseWithRPSConfig(rName),
@@ -98,13 +99,13 @@ func testAccCheckDatabaseExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No database id set")
 		}
 
-		conn := testAccProvider.Meta().(*client.Client)
+		conn := testAccProvider.Meta().(*connection)
 
 		query := client.Query{
 			Command: "SHOW DATABASES",
 		}
 
-		resp, err := conn.Query(query)
+		resp, err := conn.QueryContext(context.Background(), query)
 		if err != nil {
 			return err
 		}
@@ -168,3 +169,33 @@ resource "influxdb_database" "test" {
   }
   `, rName)
 }
+
+func TestRetentionPolicyClauses(t *testing.T) {
+	cases := []struct {
+		duration           string
+		replication        int
+		shardGroupDuration string
+		defaultPolicy      bool
+		expected           string
+	}{
+		{duration: "2d", replication: 1, expected: "DURATION 2d REPLICATION 1"},
+		{duration: "INF", replication: 2, shardGroupDuration: "1h0m0s", expected: "DURATION INF REPLICATION 2 SHARD DURATION 1h0m0s"},
+		{duration: "48h0m0s", replication: 1, shardGroupDuration: "1d", defaultPolicy: true, expected: "DURATION 48h0m0s REPLICATION 1 SHARD DURATION 1d DEFAULT"},
+	}
+
+	for _, c := range cases {
+		actual, err := retentionPolicyClauses(c.duration, c.replication, c.shardGroupDuration, c.defaultPolicy)
+		if err != nil {
+			t.Errorf("retentionPolicyClauses(%q): unexpected error %s", c.duration, err)
+		} else if actual != c.expected {
+			t.Errorf("retentionPolicyClauses(%q): expected %q, got %q", c.duration, c.expected, actual)
+		}
+	}
+
+	if _, err := retentionPolicyClauses("1d; DROP DATABASE x", 1, "", false); err == nil {
+		t.Error("expected an error on an invalid duration")
+	}
+	if _, err := retentionPolicyClauses("1d", 1, "1 h", false); err == nil {
+		t.Error("expected an error on an invalid shard group duration")
+	}
+}
@@ -0,0 +1,270 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &grantResource{}
+	_ resource.ResourceWithConfigure   = &grantResource{}
+	_ resource.ResourceWithImportState = &grantResource{}
+)
+
+func newGrantResource() resource.Resource {
+	return &grantResource{}
+}
+
+// grantResource manages the privilege of a user on a database, independently
+// of the grant set of the user.
+type grantResource struct {
+	conn *connection
+}
+
+type grantResourceModel struct {
+	ID        types.String   `tfsdk:"id"`
+	User      types.String   `tfsdk:"user"`
+	Database  types.String   `tfsdk:"database"`
+	Privilege types.String   `tfsdk:"privilege"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *grantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_grant"
+}
+
+func (r *grantResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the privilege of a user on a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The user and database names, as user/database.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the user.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the database the privilege is associated with.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"privilege": schema.StringAttribute{
+				Required:    true,
+				Description: "The privilege to grant (READ|WRITE|ALL).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("READ", "WRITE", "ALL"),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *grantResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *grantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan grantResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	user := plan.User.ValueString()
+	database := plan.Database.ValueString()
+
+	if err := grantPrivilegeOn(ctx, r.conn, plan.Privilege.ValueString(), database, user); err != nil {
+		resp.Diagnostics.AddError("Error creating grant", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(grantId(user, database))
+
+	// statements were only recorded, there is nothing to read back
+	if r.conn.config.DryRun {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	found, err := r.read(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading grant", err.Error())
+		return
+	}
+	// check that the privilege is granted
+	if !found {
+		resp.Diagnostics.AddError("Error creating grant", fmt.Sprintf("unable to grant %s on '%s' to '%s', check the user and the database exist", plan.Privilege.ValueString(), database, user))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *grantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state grantResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading grant", err.Error())
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *grantResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan grantResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// granting replaces the previous privilege of the user on the database
+	if err := grantPrivilegeOn(ctx, r.conn, plan.Privilege.ValueString(), plan.Database.ValueString(), plan.User.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error updating grant", err.Error())
+		return
+	}
+
+	// statements were only recorded, there is nothing to read back
+	if !r.conn.config.DryRun {
+		if _, err := r.read(ctx, &plan); err != nil {
+			resp.Diagnostics.AddError("Error reading grant", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *grantResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state grantResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if err := revokePrivilegeOn(ctx, r.conn, state.Privilege.ValueString(), state.Database.ValueString(), state.User.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting grant", err.Error())
+	}
+}
+
+func (r *grantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	user, database, err := parseGrantId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), user)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+}
+
+// read refreshes the privilege of model from SHOW GRANTS FOR and reports
+// whether the user has any privilege on the database.
+func (r *grantResource) read(ctx context.Context, model *grantResourceModel) (bool, error) {
+	grants, err := showGrants(ctx, r.conn, model.User.ValueString())
+	if isUserNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, grant := range grants {
+		if grant["database"] == model.Database.ValueString() {
+			model.Privilege = types.StringValue(grant["privilege"])
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// grantId returns USER/DATABASE with both parts path escaped.
+func grantId(user, database string) string {
+	return joinId(user, database)
+}
+
+func parseGrantId(id string) (string, string, error) {
+	parts, err := splitId(id, 2, "USER/DATABASE")
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,120 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInfluxDBGrant_basic(t *testing.T) {
+	resourceName := "influxdb_grant.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfig(rName, "READ"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGrantPrivilege(resourceName, "READ"),
+					resource.TestCheckResourceAttr(resourceName, "id", rName+"/"+rName),
+					resource.TestCheckResourceAttr(resourceName, "privilege", "READ"),
+					resource.TestCheckResourceAttr("influxdb_user.test", "grant.#", "0"),
+				),
+			},
+			{
+				Config: testAccGrantConfig(rName, "ALL"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGrantPrivilege(resourceName, "ALL"),
+					resource.TestCheckResourceAttr(resourceName, "privilege", "ALL"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func testAccCheckGrantPrivilege(n, privilege string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No grant id set")
+		}
+
+		conn := testAccProvider.Meta().(*connection)
+
+		grants, err := showGrants(context.Background(), conn, rs.Primary.Attributes["user"])
+		if err != nil {
+			return err
+		}
+
+		for _, grant := range grants {
+			if grant["database"] == rs.Primary.Attributes["database"] {
+				if grant["privilege"] != privilege {
+					return fmt.Errorf("Grant %q has privilege %s instead of %s", rs.Primary.ID, grant["privilege"], privilege)
+				}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Grant %q does not exist", rs.Primary.ID)
+	}
+}
+
+func testAccGrantConfig(rName, privilege string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_user" "test" {
+  name          = %[1]q
+  password      = %[1]q
+  manage_grants = false
+}
+
+resource "influxdb_grant" "test" {
+  user      = influxdb_user.test.name
+  database  = influxdb_database.test.name
+  privilege = %[2]q
+}
+`, rName, privilege)
+}
+
+func TestGrantId(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"reader", "telegraf"},
+		{"team/reader", "telegraf"},
+		{"a/b/c", "100%"},
+		{"über user", `quo"te`},
+	} {
+		id := grantId(tc[0], tc[1])
+
+		user, database, err := parseGrantId(id)
+		if err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if user != tc[0] || database != tc[1] {
+			t.Errorf("%s: expected %q and %q, got %q and %q", id, tc[0], tc[1], user, database)
+		}
+	}
+
+	for _, id := range []string{"reader", "reader/", "team/reader/telegraf", "reader/%zz"} {
+		if _, _, err := parseGrantId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ resource.Resource               = &pointsResource{}
+	_ resource.ResourceWithConfigure  = &pointsResource{}
+	_ resource.ResourceWithModifyPlan = &pointsResource{}
+)
+
+func newPointsResource() resource.Resource {
+	return &pointsResource{}
+}
+
+// pointsResource writes a line protocol body, such as reference data or
+// sentinel points, to a database.
+type pointsResource struct {
+	conn *connection
+}
+
+type pointsResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Database        types.String   `tfsdk:"database"`
+	RetentionPolicy types.String   `tfsdk:"retention_policy"`
+	Precision       types.String   `tfsdk:"precision"`
+	Body            types.String   `tfsdk:"body"`
+	File            types.String   `tfsdk:"file"`
+	DeleteOnDestroy types.Bool     `tfsdk:"delete_on_destroy"`
+	ContentHash     types.String   `tfsdk:"content_hash"`
+	Series          types.List     `tfsdk:"series"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *pointsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_points"
+}
+
+func (r *pointsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Writes points in line protocol to a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "A unique identifier of the written points.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database the points are written to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"retention_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "The retention policy the points are written to, the default one of the database when not set.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"precision": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("ns"),
+				Description: "The precision of the timestamps of the points (ns|u|ms|s|m|h).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("ns", "u", "ms", "s", "m", "h"),
+				},
+			},
+			"body": schema.StringAttribute{
+				Optional:    true,
+				Description: "The points in line protocol.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("file")),
+				},
+			},
+			"file": schema.StringAttribute{
+				Optional:    true,
+				Description: "The path of a file holding the points in line protocol.",
+			},
+			"delete_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether the written series are dropped on destroy, and when they are removed from the points.",
+			},
+			"content_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "The SHA256 hash of the written points, the points are written again when it changes.",
+			},
+			"series": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The keys of the written series, such as cpu,host=a.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *pointsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+// ModifyPlan reads the points at plan time, so that a change of the file
+// content shows up as a change of content_hash.
+func (r *pointsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan pointsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Body.IsUnknown() || plan.File.IsUnknown() {
+		plan.ContentHash = types.StringUnknown()
+		plan.Series = types.ListUnknown(types.StringType)
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	content, series, diags := readPoints(plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ContentHash = types.StringValue(hashSum(string(content)))
+	plan.Series = series
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func (r *pointsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan pointsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.write(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(uniqueId())
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read keeps the state: points are not read back, content_hash tracks what was written.
+func (r *pointsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state pointsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *pointsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state pointsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if !plan.ContentHash.Equal(state.ContentHash) || !plan.Precision.Equal(state.Precision) {
+		resp.Diagnostics.Append(r.write(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if plan.DeleteOnDestroy.ValueBool() {
+			var oldSeries, newSeries []string
+			resp.Diagnostics.Append(state.Series.ElementsAs(ctx, &oldSeries, false)...)
+			resp.Diagnostics.Append(plan.Series.ElementsAs(ctx, &newSeries, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			written := map[string]bool{}
+			for _, key := range newSeries {
+				written[key] = true
+			}
+
+			var removed []string
+			for _, key := range oldSeries {
+				if !written[key] {
+					removed = append(removed, key)
+				}
+			}
+
+			if err := dropSeries(ctx, r.conn, plan.Database.ValueString(), removed); err != nil {
+				resp.Diagnostics.AddError("Error deleting removed series", err.Error())
+				return
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *pointsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state pointsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.DeleteOnDestroy.ValueBool() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	var series []string
+	resp.Diagnostics.Append(state.Series.ElementsAs(ctx, &series, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := dropSeries(ctx, r.conn, state.Database.ValueString(), series); err != nil {
+		resp.Diagnostics.AddError("Error deleting series", err.Error())
+	}
+}
+
+// write reads the points again and writes them, the content must not have
+// changed since the plan.
+func (r *pointsResource) write(ctx context.Context, plan *pointsResourceModel) diag.Diagnostics {
+	content, series, diags := readPoints(*plan)
+	if diags.HasError() {
+		return diags
+	}
+
+	contentHash := hashSum(string(content))
+	if !plan.ContentHash.IsUnknown() && plan.ContentHash.ValueString() != contentHash {
+		diags.AddError("Points changed since the plan", fmt.Sprintf("the content of %s changed after the plan was made, plan again", plan.File.ValueString()))
+		return diags
+	}
+
+	err := r.conn.WriteContext(ctx, pointsWrite{
+		Database:        plan.Database.ValueString(),
+		RetentionPolicy: plan.RetentionPolicy.ValueString(),
+		Precision:       plan.Precision.ValueString(),
+		Body:            content,
+	})
+	if err != nil {
+		diags.AddError("Error writing points", err.Error())
+		return diags
+	}
+
+	plan.ContentHash = types.StringValue(contentHash)
+	plan.Series = series
+	return diags
+}
+
+// readPoints returns the line protocol of body or file, and the keys of its series.
+func readPoints(model pointsResourceModel) ([]byte, types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	content := []byte(model.Body.ValueString())
+	if !model.File.IsNull() {
+		var err error
+		content, err = os.ReadFile(model.File.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("file"), "Unable to read points", err.Error())
+			return nil, types.ListNull(types.StringType), diags
+		}
+	}
+
+	keys, err := seriesKeys(string(content))
+	if err != nil {
+		diags.AddError("Invalid line protocol", err.Error())
+		return nil, types.ListNull(types.StringType), diags
+	}
+
+	series := make([]attr.Value, len(keys))
+	for i, key := range keys {
+		series[i] = types.StringValue(key)
+	}
+
+	return content, types.ListValueMust(types.StringType, series), diags
+}
+
+// seriesKeys returns the distinct series keys of a line protocol body, in
+// order of appearance. Empty lines and comments are skipped.
+func seriesKeys(body string) ([]string, error) {
+	keys := []string{}
+	seen := map[string]bool{}
+
+	for n, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		end := indexUnescaped(line, ' ')
+		if end <= 0 {
+			return nil, fmt.Errorf("line %d: missing fields: %s", n+1, line)
+		}
+
+		key := line[:end]
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// dropSeries drops exactly the series of each key, in every retention
+// policy of the database. DROP SERIES matches every series having the given
+// tags, so the other tag keys of the measurement must be empty.
+func dropSeries(ctx context.Context, conn *connection, database string, keys []string) error {
+	tagKeys := map[string][]string{}
+
+	for _, key := range keys {
+		parts := splitUnescaped(key, ',')
+		measurement := unescapeLineProtocol(parts[0])
+
+		if _, ok := tagKeys[measurement]; !ok {
+			measurementTagKeys, err := showTagKeys(ctx, conn, database, measurement)
+			if err != nil {
+				return err
+			}
+			tagKeys[measurement] = measurementTagKeys
+		}
+
+		var conditions []string
+		tagged := map[string]bool{}
+		for _, tag := range parts[1:] {
+			i := indexUnescaped(tag, '=')
+			if i <= 0 {
+				return fmt.Errorf("invalid tag %q in series %q", tag, key)
+			}
+			tagKey := unescapeLineProtocol(tag[:i])
+			tagged[tagKey] = true
+			conditions = append(conditions, fmt.Sprintf("%s = %s", influxql.QuoteIdent(tagKey), influxql.QuoteString(unescapeLineProtocol(tag[i+1:]))))
+		}
+
+		for _, tagKey := range tagKeys[measurement] {
+			if !tagged[tagKey] {
+				conditions = append(conditions, fmt.Sprintf("%s = ''", influxql.QuoteIdent(tagKey)))
+			}
+		}
+
+		statement := fmt.Sprintf("DROP SERIES FROM %s", influxql.QuoteIdent(measurement))
+		if len(conditions) > 0 {
+			statement += " WHERE " + strings.Join(conditions, " AND ")
+		}
+
+		if err := execOn(ctx, conn, database, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showTagKeys returns the tag keys of a measurement, in every retention
+// policy of the database.
+func showTagKeys(ctx context.Context, conn *connection, database, measurement string) ([]string, error) {
+	series, err := showSchema(ctx, conn, database, fmt.Sprintf("SHOW TAG KEYS ON %s FROM %s", influxql.QuoteIdent(database), influxql.QuoteIdent(measurement)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tagKeys []string
+	for _, row := range series {
+		for _, result := range row.Values {
+			tagKeys = append(tagKeys, result[0].(string))
+		}
+	}
+
+	return tagKeys, nil
+}
+
+// indexUnescaped returns the index of the first c of s not escaped with a
+// backslash, or -1.
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnescaped splits s around the c not escaped with a backslash.
+func splitUnescaped(s string, c byte) []string {
+	var parts []string
+	for {
+		i := indexUnescaped(s, c)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+var lineProtocolUnescaper = strings.NewReplacer(`\,`, `,`, `\=`, `=`, `\ `, ` `)
+
+func unescapeLineProtocol(s string) string {
+	return lineProtocolUnescaper.Replace(s)
+}
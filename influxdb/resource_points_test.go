@@ -0,0 +1,250 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInfluxDBPoints_basic(t *testing.T) {
+	resourceName := "influxdb_points.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckPointsDestroyed(rName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPointsConfig(rName, `lookup,region=eu\\ west name=\"Europe\" 1700000000\nlookup,region=us name=\"America\" 1700000000`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPointsCount(rName, 2),
+					resource.TestCheckResourceAttr(resourceName, "series.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "series.0", `lookup,region=eu\ west`),
+					resource.TestCheckResourceAttrSet(resourceName, "content_hash"),
+				),
+			},
+			{
+				Config: testAccPointsConfig(rName, `lookup,region=us name=\"America\" 1700000000`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPointsCount(rName, 1),
+					resource.TestCheckResourceAttr(resourceName, "series.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBPoints_exactSeries(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPointsConfig_exactSeries(rName, true),
+				Check:  testAccCheckPointsCount(rName, 4),
+			},
+			{
+				// series with more tags than the destroyed ones are kept
+				Config: testAccPointsConfig_exactSeries(rName, false),
+				Check:  testAccCheckPointsCount(rName, 2),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBPoints_file(t *testing.T) {
+	resourceName := "influxdb_points.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	file := filepath.Join(t.TempDir(), "points.lp")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(file, []byte("sentinel value=1 1700000000\n"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccPointsFileConfig(rName, file),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content_hash", hashSum("sentinel value=1 1700000000\n")),
+				),
+			},
+			{
+				// a change of the file content rewrites the points
+				PreConfig: func() {
+					if err := os.WriteFile(file, []byte("sentinel value=2 1700000000\n"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccPointsFileConfig(rName, file),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content_hash", hashSum("sentinel value=2 1700000000\n")),
+				),
+			},
+		},
+	})
+}
+
+func TestSeriesKeys(t *testing.T) {
+	body := `# reference data
+lookup,region=eu\ west,zone=a name="Europe" 1700000000
+lookup,region=eu\ west,zone=a name="Europe, again" 1700000001
+
+sentinel value=1
+my\ measurement,tag\=key=a\,b value=1
+`
+
+	keys, err := seriesKeys(body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{`lookup,region=eu\ west,zone=a`, `sentinel`, `my\ measurement,tag\=key=a\,b`}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected %q, got %q", expected, keys)
+	}
+
+	if _, err := seriesKeys("cpu,host=a"); err == nil {
+		t.Fatal("expected an error on a line without fields")
+	}
+}
+
+func TestDropSeries(t *testing.T) {
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case `SHOW TAG KEYS ON "telegraf" FROM "my measurement"`:
+			w.Write([]byte(`{"results":[{"statement_id":0,"series":[{"name":"my measurement","columns":["tagKey"],"values":[["host"],["quote"],["tag=key"]]}]}]}`))
+		default:
+			w.Write([]byte(`{"results":[{"statement_id":0,"series":[{"name":"sentinel","columns":["tagKey"],"values":[["host"]]}]}]}`))
+		}
+	})
+	defer server.Close()
+
+	conn := testConnection(t, server.URL)
+	conn.config.DryRun = true
+	conn.config.StatementLog = filepath.Join(t.TempDir(), "statements.influxql")
+
+	if err := dropSeries(context.Background(), conn, "telegraf", []string{`my\ measurement,tag\=key=a\,b,quote=it's`, `sentinel`}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	content, err := os.ReadFile(conn.config.StatementLog)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := `DROP SERIES FROM "my measurement" WHERE "tag=key" = 'a,b' AND "quote" = 'it\'s' AND "host" = '';
+DROP SERIES FROM "sentinel" WHERE "host" = '';
+`
+	if string(content) != expected {
+		t.Fatalf("expected %q, got %q", expected, content)
+	}
+}
+
+func testAccCheckPointsCount(database string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		resp, err := query(context.Background(), conn, fmt.Sprintf(`SELECT count("name") FROM %q.."lookup"`, database))
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Results) == 0 || len(resp.Results[0].Series) == 0 {
+			return fmt.Errorf("No points written to %s", database)
+		}
+
+		if actual := fmt.Sprint(resp.Results[0].Series[0].Values[0][1]); actual != fmt.Sprint(count) {
+			return fmt.Errorf("Expected %d points in %s, got %s", count, database, actual)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckPointsDestroyed(database string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		resp, err := query(context.Background(), conn, fmt.Sprintf(`SHOW SERIES ON %q`, database))
+		if isDatabaseNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Results) > 0 && len(resp.Results[0].Series) > 0 {
+			return fmt.Errorf("Series of %s were not dropped", database)
+		}
+
+		return nil
+	}
+}
+
+func testAccPointsConfig(rName, body string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_points" "test" {
+  database          = influxdb_database.test.name
+  precision         = "s"
+  body              = "%[2]s"
+  delete_on_destroy = true
+}
+`, rName, body)
+}
+
+func testAccPointsConfig_exactSeries(rName string, withTest bool) string {
+	config := fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_points" "other" {
+  database  = influxdb_database.test.name
+  precision = "s"
+  body      = "lookup,region=us,zone=a name=\"America A\" 1700000000\nlookup,region=eu name=\"Europe\" 1700000000"
+}
+`, rName)
+
+	if withTest {
+		config += `
+resource "influxdb_points" "test" {
+  database          = influxdb_database.test.name
+  precision         = "s"
+  body              = "lookup,region=us name=\"America\" 1700000000\nlookup name=\"Anywhere\" 1700000000"
+  delete_on_destroy = true
+}
+`
+	}
+
+	return config
+}
+
+func testAccPointsFileConfig(rName, file string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_points" "test" {
+  database  = influxdb_database.test.name
+  precision = "s"
+  file      = %[2]q
+}
+`, rName, file)
+}
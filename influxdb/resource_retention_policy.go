@@ -0,0 +1,341 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &retentionPolicyResource{}
+	_ resource.ResourceWithConfigure   = &retentionPolicyResource{}
+	_ resource.ResourceWithImportState = &retentionPolicyResource{}
+	_ resource.ResourceWithModifyPlan  = &retentionPolicyResource{}
+)
+
+func newRetentionPolicyResource() resource.Resource {
+	return &retentionPolicyResource{}
+}
+
+// retentionPolicyResource manages a single retention policy of a database
+// owned by another configuration.
+type retentionPolicyResource struct {
+	conn *connection
+}
+
+type retentionPolicyResourceModel struct {
+	ID            types.String   `tfsdk:"id"`
+	Database      types.String   `tfsdk:"database"`
+	Name          types.String   `tfsdk:"name"`
+	Duration      types.String   `tfsdk:"duration"`
+	Replication   types.Int64    `tfsdk:"replication"`
+	ShardDuration types.String   `tfsdk:"shard_duration"`
+	Default       types.Bool     `tfsdk:"default"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *retentionPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_retention_policy"
+}
+
+func (r *retentionPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a retention policy of an existing database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The database and retention policy names, as database/name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the retention policy.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the retention policy.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"duration": schema.StringAttribute{
+				Required:    true,
+				Description: "How long the data is kept, as an InfluxQL duration such as 2d, 48h0m0s or INF.",
+				Validators: []validator.String{
+					influxDurationValidator{},
+				},
+			},
+			"replication": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1),
+				Description: "How many copies of the data are stored in a cluster.",
+			},
+			"shard_duration": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The time range covered by a shard group. Computed by the server from duration when not set.",
+				Validators: []validator.String{
+					influxDurationValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"default": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the retention policy is the default one of the database. When unset, it is read from the server.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *retentionPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *retentionPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan retentionPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	database := plan.Database.ValueString()
+	name := plan.Name.ValueString()
+
+	err := createRetentionPolicy(ctx, r.conn, name, plan.Duration.ValueString(), int(plan.Replication.ValueInt64()), plan.ShardDuration.ValueString(), plan.Default.ValueBool(), database)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating retention policy", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(retentionPolicyId(database, name))
+
+	// statements were only recorded, there is nothing to read back
+	if r.conn.config.DryRun {
+		if plan.ShardDuration.IsUnknown() {
+			plan.ShardDuration = types.StringValue("")
+		}
+		if plan.Default.IsUnknown() {
+			plan.Default = types.BoolValue(false)
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	found, err := r.read(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading retention policy", err.Error())
+		return
+	}
+	// check that the retention policy is created
+	if !found {
+		resp.Diagnostics.AddError("Error creating retention policy", fmt.Sprintf("unable to create retention policy '%s' on '%s', check the database exists and the durations", name, database))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *retentionPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state retentionPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading retention policy", err.Error())
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *retentionPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan retentionPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	err := updateRetentionPolicy(ctx, r.conn, plan.Name.ValueString(), plan.Duration.ValueString(), int(plan.Replication.ValueInt64()), plan.ShardDuration.ValueString(), plan.Default.ValueBool(), plan.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating retention policy", err.Error())
+		return
+	}
+
+	// statements were only recorded, there is nothing to read back
+	if !r.conn.config.DryRun {
+		if _, err := r.read(ctx, &plan); err != nil {
+			resp.Diagnostics.AddError("Error reading retention policy", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// ModifyPlan refuses to unset the default retention policy: InfluxDB can only
+// make another retention policy the default one.
+func (r *retentionPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, config types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("default"), &state)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default"), &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.ValueBool() && !config.IsUnknown() && !config.IsNull() && !config.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default"),
+			"Default retention policy cannot be unset",
+			"A database always has a default retention policy: make another retention policy the default one and remove default from this one, it is then read from the server.",
+		)
+	}
+}
+
+func (r *retentionPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state retentionPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if err := deleteRetentionPolicy(ctx, r.conn, state.Name.ValueString(), state.Database.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting retention policy", err.Error())
+	}
+}
+
+func (r *retentionPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	database, name, err := parseRetentionPolicyId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// read refreshes model from SHOW RETENTION POLICIES and reports whether the
+// retention policy exists. Durations equal to the configured ones are kept as
+// written in the configuration.
+func (r *retentionPolicyResource) read(ctx context.Context, model *retentionPolicyResourceModel) (bool, error) {
+	policies, err := showRetentionPolicies(ctx, r.conn, model.Database.ValueString())
+	if isDatabaseNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, policy := range policies {
+		if policy["name"].(string) != model.Name.ValueString() {
+			continue
+		}
+
+		if duration := policy["duration"].(string); !sameInfluxDuration(model.Duration.ValueString(), duration) {
+			model.Duration = types.StringValue(duration)
+		}
+		if shardDuration := policy["shardgroupduration"].(string); !sameInfluxDuration(model.ShardDuration.ValueString(), shardDuration) {
+			model.ShardDuration = types.StringValue(shardDuration)
+		}
+		model.Replication = types.Int64Value(int64(policy["replication"].(int)))
+		model.Default = types.BoolValue(policy["default"].(bool))
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// retentionPolicyId returns DATABASE/NAME with both parts path escaped.
+func retentionPolicyId(database, name string) string {
+	return joinId(database, name)
+}
+
+func parseRetentionPolicyId(id string) (string, string, error) {
+	parts, err := splitId(id, 2, "DATABASE/NAME")
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
+}
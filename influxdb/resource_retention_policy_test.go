@@ -0,0 +1,245 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInfluxDBRetentionPolicy_basic(t *testing.T) {
+	resourceName := "influxdb_retention_policy.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRetentionPolicyConfig(rName, "2d"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRetentionPolicyExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", rName+"/2days"),
+					resource.TestCheckResourceAttr(resourceName, "duration", "2d"),
+					resource.TestCheckResourceAttr(resourceName, "replication", "1"),
+					resource.TestCheckResourceAttr(resourceName, "default", "false"),
+				),
+			},
+			{
+				Config: testAccRetentionPolicyConfig(rName, "72h0m0s"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRetentionPolicyExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "duration", "72h0m0s"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBRetentionPolicy_moveDefault(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRetentionPolicyConfig_default(rName, "hot"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("influxdb_retention_policy.hot", "default", "true"),
+					resource.TestCheckResourceAttr("influxdb_retention_policy.cold", "default", "false"),
+				),
+			},
+			{
+				// the default retention policy cannot be unset
+				Config:      testAccRetentionPolicyConfig_unsetDefault(rName),
+				ExpectError: regexp.MustCompile("Default retention policy cannot be unset"),
+			},
+			{
+				// the default moves in a single apply, and the plan is then empty
+				Config: testAccRetentionPolicyConfig_default(rName, "cold"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRetentionPolicyDefault("influxdb_retention_policy.cold"),
+					resource.TestCheckResourceAttr("influxdb_retention_policy.cold", "default", "true"),
+				),
+			},
+			{
+				// the previous default is read from the server once refreshed
+				Config: testAccRetentionPolicyConfig_default(rName, "cold"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("influxdb_retention_policy.hot", "default", "false"),
+					resource.TestCheckResourceAttr("influxdb_retention_policy.cold", "default", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBRetentionPolicy_escapedId(t *testing.T) {
+	resourceName := "influxdb_retention_policy.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test 100%")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRetentionPolicyConfig(rName, "2d"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRetentionPolicyExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", retentionPolicyId(rName, "2days")),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestRetentionPolicyId(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"telegraf", "2days"},
+		{"my/db", "rp/with/slashes"},
+		{"100%", "%2F"},
+		{"über db", `quo"te`},
+	} {
+		id := retentionPolicyId(tc[0], tc[1])
+
+		database, name, err := parseRetentionPolicyId(id)
+		if err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if database != tc[0] || name != tc[1] {
+			t.Errorf("%s: expected %q and %q, got %q and %q", id, tc[0], tc[1], database, name)
+		}
+	}
+
+	for _, id := range []string{"telegraf", "telegraf/", "a/b/c", "telegraf/%zz"} {
+		if _, _, err := parseRetentionPolicyId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
+
+func testAccCheckRetentionPolicyDefault(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*connection)
+
+		policies, err := showRetentionPolicies(context.Background(), conn, rs.Primary.Attributes["database"])
+		if err != nil {
+			return err
+		}
+
+		for _, policy := range policies {
+			if policy["default"].(bool) {
+				if policy["name"].(string) != rs.Primary.Attributes["name"] {
+					return fmt.Errorf("Expected %q to be the default retention policy, got %q", rs.Primary.Attributes["name"], policy["name"])
+				}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("No default retention policy on %q", rs.Primary.Attributes["database"])
+	}
+}
+
+func testAccCheckRetentionPolicyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No retention policy id set")
+		}
+
+		conn := testAccProvider.Meta().(*connection)
+
+		policies, err := showRetentionPolicies(context.Background(), conn, rs.Primary.Attributes["database"])
+		if err != nil {
+			return err
+		}
+
+		for _, policy := range policies {
+			if policy["name"].(string) == rs.Primary.Attributes["name"] {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Retention policy %q does not exist", rs.Primary.ID)
+	}
+}
+
+func testAccRetentionPolicyConfig(rName, duration string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_retention_policy" "test" {
+  database = influxdb_database.test.name
+  name     = "2days"
+  duration = %[2]q
+}
+`, rName, duration)
+}
+
+func testAccRetentionPolicyConfig_default(rName, defaultPolicy string) string {
+	defaults := map[string]string{defaultPolicy: "\n  default  = true"}
+
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_retention_policy" "hot" {
+  database = influxdb_database.test.name
+  name     = "hot"
+  duration = "1d"%[2]s
+}
+
+resource "influxdb_retention_policy" "cold" {
+  database = influxdb_database.test.name
+  name     = "cold"
+  duration = "30d"%[3]s
+}
+`, rName, defaults["hot"], defaults["cold"])
+}
+
+func testAccRetentionPolicyConfig_unsetDefault(rName string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_retention_policy" "hot" {
+  database = influxdb_database.test.name
+  name     = "hot"
+  duration = "1d"
+  default  = false
+}
+
+resource "influxdb_retention_policy" "cold" {
+  database = influxdb_database.test.name
+  name     = "cold"
+  duration = "30d"
+}
+`, rName)
+}
@@ -0,0 +1,234 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ resource.Resource              = &seriesCleanupResource{}
+	_ resource.ResourceWithConfigure = &seriesCleanupResource{}
+)
+
+func newSeriesCleanupResource() resource.Resource {
+	return &seriesCleanupResource{}
+}
+
+// seriesCleanupResource runs a DELETE, DROP SERIES or DROP MEASUREMENT
+// statement once, when it is created.
+type seriesCleanupResource struct {
+	conn *connection
+}
+
+type seriesCleanupResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Database    types.String   `tfsdk:"database"`
+	Statement   types.String   `tfsdk:"statement"`
+	Measurement types.String   `tfsdk:"measurement"`
+	ExecutedAt  types.String   `tfsdk:"executed_at"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *seriesCleanupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_series_cleanup"
+}
+
+func (r *seriesCleanupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a DELETE, DROP SERIES or DROP MEASUREMENT statement once, and again when it changes.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "A unique identifier of the execution.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database the statement runs on.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"statement": schema.StringAttribute{
+				Required:    true,
+				Description: "The DELETE, DROP SERIES or DROP MEASUREMENT statement.",
+				Validators: []validator.String{
+					cleanupStatementValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"measurement": schema.StringAttribute{
+				Computed:    true,
+				Description: "The measurement, or measurement regex, of the statement. Empty when the statement applies to every measurement.",
+			},
+			"executed_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the statement was run, in RFC 3339 format.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *seriesCleanupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *seriesCleanupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan seriesCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	statement, measurement, err := parseCleanupStatement(plan.Statement.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid statement", err.Error())
+		return
+	}
+
+	if err := execOn(ctx, r.conn, plan.Database.ValueString(), statement); err != nil {
+		resp.Diagnostics.AddError("Error running statement", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(uniqueId())
+	plan.Measurement = types.StringValue(measurement)
+	plan.ExecutedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read keeps the state: the statement ran once, there is nothing to read back.
+func (r *seriesCleanupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state seriesCleanupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update only stores new timeouts, a new statement is run by a new resource.
+func (r *seriesCleanupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan seriesCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete only forgets the execution, deleted data cannot be restored.
+func (r *seriesCleanupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+const (
+	cleanupIdent  = `"(?:[^"\\]|\\.)*"|[A-Za-z_][A-Za-z0-9_]*`
+	cleanupSource = cleanupIdent + `|/(?:[^/\\]|\\.)*/`
+)
+
+var (
+	// DELETE and DROP SERIES take an optional FROM measurement, or regex, and
+	// an optional WHERE predicate, one of them being required.
+	cleanupSeriesStatement = regexp.MustCompile(`(?is)^(DELETE|DROP\s+SERIES)(?:\s+FROM\s+(` + cleanupSource + `))?(?:\s+WHERE\s+(.+))?$`)
+	// DROP MEASUREMENT takes a single measurement name.
+	cleanupMeasurementStatement = regexp.MustCompile(`(?is)^DROP\s+MEASUREMENT\s+(` + cleanupIdent + `)$`)
+)
+
+// parseCleanupStatement checks statement is a single DELETE, DROP SERIES or
+// DROP MEASUREMENT statement, and returns it trimmed with the affected
+// measurement.
+func parseCleanupStatement(statement string) (string, string, error) {
+	statement = strings.TrimSpace(statement)
+	statement = strings.TrimSpace(strings.TrimSuffix(statement, ";"))
+
+	if hasStatementSeparator(statement) {
+		return "", "", fmt.Errorf("expected a single statement: %s", statement)
+	}
+
+	if match := cleanupMeasurementStatement.FindStringSubmatch(statement); match != nil {
+		return statement, influxql.UnquoteIdent(match[1]), nil
+	}
+
+	match := cleanupSeriesStatement.FindStringSubmatch(statement)
+	if match == nil {
+		return "", "", fmt.Errorf("expected a DELETE, DROP SERIES or DROP MEASUREMENT statement: %s", statement)
+	}
+	if match[2] == "" && match[3] == "" {
+		return "", "", fmt.Errorf("expected a FROM or WHERE clause: %s", statement)
+	}
+
+	return statement, influxql.UnquoteIdent(match[2]), nil
+}
+
+// hasStatementSeparator reports whether s holds a ';' outside of quoted
+// identifiers, string literals and regexes.
+func hasStatementSeparator(s string) bool {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\'' || c == '/'):
+			quote = c
+		case quote == 0 && c == ';':
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupStatementValidator checks a string attribute is a statement
+// accepted by parseCleanupStatement.
+type cleanupStatementValidator struct{}
+
+func (v cleanupStatementValidator) Description(ctx context.Context) string {
+	return "value must be a single DELETE, DROP SERIES or DROP MEASUREMENT statement"
+}
+
+func (v cleanupStatementValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cleanupStatementValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, _, err := parseCleanupStatement(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid statement", err.Error())
+	}
+}
@@ -0,0 +1,125 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInfluxDBSeriesCleanup_basic(t *testing.T) {
+	resourceName := "influxdb_series_cleanup.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSeriesCleanupConfig(rName, `DROP SERIES FROM \"cpu\" WHERE \"host\" = 'bad'`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSeriesCount(rName, 1),
+					resource.TestCheckResourceAttr(resourceName, "measurement", "cpu"),
+					resource.TestCheckResourceAttrSet(resourceName, "executed_at"),
+				),
+			},
+			{
+				Config: testAccSeriesCleanupConfig(rName, `DELETE FROM cpu WHERE time < now()`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSeriesCount(rName, 0),
+				),
+			},
+			{
+				Config:      testAccSeriesCleanupConfig(rName, `DROP DATABASE \"x\"`),
+				ExpectError: regexp.MustCompile("expected a DELETE, DROP SERIES or DROP MEASUREMENT statement"),
+			},
+		},
+	})
+}
+
+func TestParseCleanupStatement(t *testing.T) {
+	cases := []struct {
+		statement   string
+		expected    string
+		measurement string
+	}{
+		{statement: `DROP SERIES FROM "cpu" WHERE "host" = 'x'`, expected: `DROP SERIES FROM "cpu" WHERE "host" = 'x'`, measurement: "cpu"},
+		{statement: "  delete from cpu where time < '2024-01-01T00:00:00Z';\n", expected: `delete from cpu where time < '2024-01-01T00:00:00Z'`, measurement: "cpu"},
+		{statement: `DELETE WHERE time < now() - 30d`, expected: `DELETE WHERE time < now() - 30d`, measurement: ""},
+		{statement: `DROP SERIES FROM /^tmp_/`, expected: `DROP SERIES FROM /^tmp_/`, measurement: "/^tmp_/"},
+		{statement: `DROP MEASUREMENT "my \"quoted\" measurement"`, expected: `DROP MEASUREMENT "my \"quoted\" measurement"`, measurement: `my "quoted" measurement`},
+		{statement: `DROP SERIES FROM "m;x" WHERE "host" = 'a;b' OR "host" =~ /c;d/`, expected: `DROP SERIES FROM "m;x" WHERE "host" = 'a;b' OR "host" =~ /c;d/`, measurement: "m;x"},
+	}
+
+	for _, c := range cases {
+		statement, measurement, err := parseCleanupStatement(c.statement)
+		if err != nil {
+			t.Errorf("parseCleanupStatement(%q): unexpected error %s", c.statement, err)
+			continue
+		}
+		if statement != c.expected || measurement != c.measurement {
+			t.Errorf("parseCleanupStatement(%q): expected %q and %q, got %q and %q", c.statement, c.expected, c.measurement, statement, measurement)
+		}
+	}
+
+	for _, statement := range []string{
+		``,
+		`DELETE`,
+		`DROP SERIES`,
+		`DROP DATABASE "telegraf"`,
+		`SELECT * FROM cpu`,
+		`DROP MEASUREMENT cpu WHERE host = 'x'`,
+		`DELETE FROM "telegraf"."autogen"."cpu"`,
+		`DELETE FROM cpu; DROP DATABASE telegraf`,
+		`DROP SERIES FROM cpu WHERE host = 'x'; DROP USER admin`,
+	} {
+		if _, _, err := parseCleanupStatement(statement); err == nil {
+			t.Errorf("parseCleanupStatement(%q): expected an error", statement)
+		}
+	}
+}
+
+func testAccCheckSeriesCount(database string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		resp, err := query(context.Background(), conn, fmt.Sprintf(`SHOW SERIES ON %q FROM "cpu"`, database))
+		if err != nil {
+			return err
+		}
+
+		actual := 0
+		for _, series := range resp.Results[0].Series {
+			actual += len(series.Values)
+		}
+
+		if actual != count {
+			return fmt.Errorf("Expected %d series in %s, got %d", count, database, actual)
+		}
+
+		return nil
+	}
+}
+
+func testAccSeriesCleanupConfig(rName, statement string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_points" "test" {
+  database = influxdb_database.test.name
+  body     = "cpu,host=good value=1 1700000000000000000\ncpu,host=bad value=1 1700000000000000000"
+}
+
+resource "influxdb_series_cleanup" "test" {
+  database  = influxdb_database.test.name
+  statement = "%[2]s"
+
+  depends_on = [influxdb_points.test]
+}
+`, rName, statement)
+}
@@ -0,0 +1,306 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
+)
+
+var (
+	_ resource.Resource                = &subscriptionResource{}
+	_ resource.ResourceWithConfigure   = &subscriptionResource{}
+	_ resource.ResourceWithImportState = &subscriptionResource{}
+)
+
+func newSubscriptionResource() resource.Resource {
+	return &subscriptionResource{}
+}
+
+// subscriptionResource manages a subscription forwarding the writes of a
+// retention policy, to Kapacitor or another server.
+type subscriptionResource struct {
+	conn *connection
+}
+
+type subscriptionResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	Database        types.String   `tfsdk:"database"`
+	RetentionPolicy types.String   `tfsdk:"retention_policy"`
+	Mode            types.String   `tfsdk:"mode"`
+	Destinations    types.List     `tfsdk:"destinations"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *subscriptionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subscription"
+}
+
+func (r *subscriptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a subscription forwarding the writes of a retention policy to other hosts.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The database, retention policy and subscription names, as database/retention_policy/name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the subscription.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The database of the subscription.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"retention_policy": schema.StringAttribute{
+				Required:    true,
+				Description: "The retention policy whose writes are forwarded.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mode": schema.StringAttribute{
+				Required:    true,
+				Description: "Whether writes are sent to ANY or to ALL the destinations.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("ANY", "ALL"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"destinations": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The URLs the writes are forwarded to, such as http://kapacitor:9092 or udp://relay:9100.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *subscriptionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.conn = providerConnection(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *subscriptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan subscriptionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var destinations []string
+	resp.Diagnostics.Append(plan.Destinations.ElementsAs(ctx, &destinations, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+	database := plan.Database.ValueString()
+	retentionPolicy := plan.RetentionPolicy.ValueString()
+
+	quotedDestinations := make([]string, len(destinations))
+	for i, destination := range destinations {
+		quotedDestinations[i] = influxql.QuoteString(destination)
+	}
+
+	err := exec(ctx, r.conn, fmt.Sprintf("CREATE SUBSCRIPTION %s ON %s DESTINATIONS %s %s", influxql.QuoteIdent(name), influxql.QuoteIdents(database, retentionPolicy), plan.Mode.ValueString(), strings.Join(quotedDestinations, ", ")))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating subscription", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(subscriptionId(database, retentionPolicy, name))
+
+	// statements were only recorded, there is nothing to read back
+	if r.conn.config.DryRun {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	found, err := r.read(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading subscription", err.Error())
+		return
+	}
+	// check that the subscription is created
+	if !found {
+		resp.Diagnostics.AddError("Error creating subscription", fmt.Sprintf("unable to create subscription '%s' on '%s'.'%s', check the retention policy and the destinations", name, database, retentionPolicy))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *subscriptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state subscriptionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading subscription", err.Error())
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update only stores new timeouts, every other change recreates the subscription.
+func (r *subscriptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan subscriptionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *subscriptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state subscriptionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := exec(ctx, r.conn, fmt.Sprintf("DROP SUBSCRIPTION %s ON %s", influxql.QuoteIdent(state.Name.ValueString()), influxql.QuoteIdents(state.Database.ValueString(), state.RetentionPolicy.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting subscription", err.Error())
+	}
+}
+
+func (r *subscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	database, retentionPolicy, name, err := parseSubscriptionId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("retention_policy"), retentionPolicy)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// read refreshes the mode and destinations of model and reports whether the
+// subscription exists.
+func (r *subscriptionResource) read(ctx context.Context, model *subscriptionResourceModel) (bool, error) {
+	// InfluxDB doesn't have a command to check the existence of a single
+	// subscription, so we instead must read the list of all subscriptions and
+	// see if ours is present in it.
+	resp, err := query(ctx, r.conn, "SHOW SUBSCRIPTIONS")
+	if err != nil {
+		return false, err
+	}
+
+	for _, series := range resp.Results[0].Series {
+		if series.Name != model.Database.ValueString() {
+			continue
+		}
+
+		for _, result := range series.Values {
+			if result[0].(string) != model.RetentionPolicy.ValueString() || result[1].(string) != model.Name.ValueString() {
+				continue
+			}
+
+			destinations := []attr.Value{}
+			for _, destination := range result[3].([]interface{}) {
+				destinations = append(destinations, types.StringValue(destination.(string)))
+			}
+
+			model.Mode = types.StringValue(result[2].(string))
+			model.Destinations = types.ListValueMust(types.StringType, destinations)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// subscriptionId returns DATABASE/RETENTION_POLICY/NAME with every part path
+// escaped.
+func subscriptionId(database, retentionPolicy, name string) string {
+	return joinId(database, retentionPolicy, name)
+}
+
+func parseSubscriptionId(id string) (string, string, string, error) {
+	parts, err := splitId(id, 3, "DATABASE/RETENTION_POLICY/NAME")
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
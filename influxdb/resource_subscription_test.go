@@ -0,0 +1,117 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInfluxDBSubscription_basic(t *testing.T) {
+	resourceName := "influxdb_subscription.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSubscriptionConfig(rName, "ANY"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSubscriptionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", rName+"/autogen/"+rName),
+					resource.TestCheckResourceAttr(resourceName, "mode", "ANY"),
+					resource.TestCheckResourceAttr(resourceName, "destinations.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "destinations.0", "http://kapacitor-1:9092"),
+				),
+			},
+			{
+				Config: testAccSubscriptionConfig(rName, "ALL"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSubscriptionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "mode", "ALL"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func testAccCheckSubscriptionExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No subscription id set")
+		}
+
+		conn := testAccProvider.Meta().(*connection)
+
+		resp, err := query(context.Background(), conn, "SHOW SUBSCRIPTIONS")
+		if err != nil {
+			return err
+		}
+
+		for _, series := range resp.Results[0].Series {
+			if series.Name == rs.Primary.Attributes["database"] {
+				for _, result := range series.Values {
+					if result[0].(string) == rs.Primary.Attributes["retention_policy"] && result[1].(string) == rs.Primary.Attributes["name"] {
+						return nil
+					}
+				}
+			}
+		}
+
+		return fmt.Errorf("Subscription %q does not exist", rs.Primary.ID)
+	}
+}
+
+func testAccSubscriptionConfig(rName, mode string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_subscription" "test" {
+  name             = %[1]q
+  database         = influxdb_database.test.name
+  retention_policy = "autogen"
+  mode             = %[2]q
+  destinations     = ["http://kapacitor-1:9092", "http://kapacitor-2:9092"]
+}
+`, rName, mode)
+}
+
+func TestSubscriptionId(t *testing.T) {
+	for _, tc := range [][3]string{
+		{"telegraf", "autogen", "kapacitor"},
+		{"my db", "1 week", "kapacitor/prod"},
+		{"100%", "%2F", `quo"te`},
+	} {
+		id := subscriptionId(tc[0], tc[1], tc[2])
+
+		database, retentionPolicy, name, err := parseSubscriptionId(id)
+		if err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if database != tc[0] || retentionPolicy != tc[1] || name != tc[2] {
+			t.Errorf("%s: expected %q, %q and %q, got %q, %q and %q", id, tc[0], tc[1], tc[2], database, retentionPolicy, name)
+		}
+	}
+
+	for _, id := range []string{"telegraf/autogen", "telegraf//kapacitor", "telegraf/autogen/kapacitor/prod", "telegraf/autogen/%zz"} {
+		if _, _, _, err := parseSubscriptionId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
@@ -1,24 +1,35 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/influxdata/influxdb/client"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
 )
 
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		Create: createUser,
-		Read:   readUser,
-		Update: updateUser,
-		Delete: deleteUser,
+		CreateContext: createUser,
+		ReadContext:   readUser,
+		UpdateContext: updateUser,
+		DeleteContext: deleteUser,
+		CustomizeDiff: customizeUserDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -28,7 +39,6 @@ func resourceUser() *schema.Resource {
 			"password": {
 				Type:      schema.TypeString,
 				Required:  true,
-				ForceNew:  true,
 				Sensitive: true,
 				StateFunc: hashSum,
 			},
@@ -37,6 +47,11 @@ func resourceUser() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"manage_grants": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"grant": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -58,8 +73,8 @@ func resourceUser() *schema.Resource {
 	}
 }
 
-func createUser(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func createUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 
 	name := d.Get("name").(string)
 	password := d.Get("password").(string)
@@ -70,17 +85,8 @@ func createUser(d *schema.ResourceData, meta interface{}) error {
 		admin_privileges = "WITH ALL PRIVILEGES"
 	}
 
-	queryStr := fmt.Sprintf("CREATE USER %q WITH PASSWORD '%s' %s", name, password, admin_privileges)
-	query := client.Query{
-		Command: queryStr,
-	}
-
-	resp, err := conn.Query(query)
-	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+	if err := exec(ctx, conn, fmt.Sprintf("CREATE USER %s WITH PASSWORD %s %s", influxql.QuoteIdent(name), influxql.QuoteString(password), admin_privileges)); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(name)
@@ -89,56 +95,61 @@ func createUser(d *schema.ResourceData, meta interface{}) error {
 		grants := v.(*schema.Set).List()
 		for _, vv := range grants {
 			grant := vv.(map[string]interface{})
-			if err := grantPrivilegeOn(conn, grant["privilege"].(string), grant["database"].(string), name); err != nil {
-				return err
+			if err := grantPrivilegeOn(ctx, conn, grant["privilege"].(string), grant["database"].(string), name); err != nil {
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	return readUser(d, meta)
+	// statements were only recorded, there is nothing to read back
+	if conn.config.DryRun {
+		d.Set("admin", d.Get("admin").(bool))
+		return nil
+	}
+
+	return readUser(ctx, d, meta)
 }
 
-func grantPrivilegeOn(conn *client.Client, privilege, database, user string) error {
-	return exec(conn, fmt.Sprintf("GRANT %s ON %q TO %q", privilege, database, user))
+func grantPrivilegeOn(ctx context.Context, conn *connection, privilege, database, user string) error {
+	return exec(ctx, conn, fmt.Sprintf("GRANT %s ON %s TO %s", privilege, influxql.QuoteIdent(database), influxql.QuoteIdent(user)))
 }
 
-func revokePrivilegeOn(conn *client.Client, privilege, database, user string) error {
-	return exec(conn, fmt.Sprintf("REVOKE %s ON %q FROM %q", privilege, database, user))
+func revokePrivilegeOn(ctx context.Context, conn *connection, privilege, database, user string) error {
+	return exec(ctx, conn, fmt.Sprintf("REVOKE %s ON %s FROM %s", privilege, influxql.QuoteIdent(database), influxql.QuoteIdent(user)))
 }
 
-func grantAllOn(conn *client.Client, user string) error {
-	return exec(conn, fmt.Sprintf("GRANT ALL PRIVILEGES TO %q", user))
+func grantAllOn(ctx context.Context, conn *connection, user string) error {
+	return exec(ctx, conn, fmt.Sprintf("GRANT ALL PRIVILEGES TO %s", influxql.QuoteIdent(user)))
 }
 
-func revokeAllOn(conn *client.Client, user string) error {
-	return exec(conn, fmt.Sprintf("REVOKE ALL PRIVILEGES FROM %q", user))
+func revokeAllOn(ctx context.Context, conn *connection, user string) error {
+	return exec(ctx, conn, fmt.Sprintf("REVOKE ALL PRIVILEGES FROM %s", influxql.QuoteIdent(user)))
 }
 
-func readUser(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+func readUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 	name := d.Id()
 
 	// InfluxDB doesn't have a command to check the existence of a single
 	// User, so we instead must read the list of all Users and see
 	// if ours is present in it.
-	query := client.Query{
-		Command: "SHOW USERS",
-	}
-
-	resp, err := conn.Query(query)
+	users, err := showUsers(ctx, conn)
 	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+		return diag.FromErr(err)
 	}
 
+	// name is only unknown when the user is imported
+	importing := d.Get("name").(string) == ""
+
 	var found = false
-	for _, result := range resp.Results[0].Series[0].Values {
-		if result[0] == name {
+	for _, user := range users {
+		if user["name"] == name {
 			found = true
 			d.Set("name", name)
-			d.Set("admin", result[1].(bool))
+			d.Set("admin", user["admin"].(bool))
+			if importing {
+				d.Set("manage_grants", true)
+			}
 			break
 		}
 	}
@@ -150,59 +161,110 @@ func readUser(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	return readGrants(d, meta)
+	// Grants are left to influxdb_grant resources when manage_grants is
+	// false, otherwise they would show up as drift to revoke.
+	if !d.Get("manage_grants").(bool) {
+		return nil
+	}
+
+	return diag.FromErr(readGrants(ctx, d, meta))
 }
 
-func readGrants(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
-	name := d.Id()
+// showUsers returns the name and admin flag of all users.
+func showUsers(ctx context.Context, conn *connection) ([]map[string]interface{}, error) {
+	resp, err := query(ctx, conn, "SHOW USERS")
+	if err != nil {
+		return nil, err
+	}
 
-	query := client.Query{
-		Command: fmt.Sprintf("SHOW GRANTS FOR %q", name),
+	users := []map[string]interface{}{}
+	for _, series := range resp.Results[0].Series {
+		for _, result := range series.Values {
+			users = append(users, map[string]interface{}{
+				"name":  result[0].(string),
+				"admin": result[1].(bool),
+			})
+		}
 	}
 
-	resp, err := conn.Query(query)
+	return users, nil
+}
+
+func readGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*connection)
+	name := d.Id()
+
+	grants, err := showGrants(ctx, conn, name)
 	if err != nil {
 		return err
 	}
 
-	if resp.Err != nil {
-		return resp.Err
+	d.Set("grant", grants)
+	return nil
+}
+
+// showGrants returns the privileges of a user on each database, in the
+// format of the grant elements.
+func showGrants(ctx context.Context, conn *connection, user string) ([]map[string]string, error) {
+	resp, err := query(ctx, conn, fmt.Sprintf("SHOW GRANTS FOR %s", influxql.QuoteIdent(user)))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Results[0].Err != nil {
+		return nil, resp.Results[0].Err
 	}
 
 	var grants = []map[string]string{}
-	for _, result := range resp.Results[0].Series[0].Values {
-		if result[1].(string) != "NO PRIVILEGES" {
-			var grant = map[string]string{
-				"database":  result[0].(string),
-				"privilege": strings.Replace(strings.ToUpper(result[1].(string)), "ALL PRIVILEGES", "ALL", 1),
+	for _, series := range resp.Results[0].Series {
+		for _, result := range series.Values {
+			if result[1].(string) != "NO PRIVILEGES" {
+				var grant = map[string]string{
+					"database":  result[0].(string),
+					"privilege": strings.Replace(strings.ToUpper(result[1].(string)), "ALL PRIVILEGES", "ALL", 1),
+				}
+				grants = append(grants, grant)
 			}
-			grants = append(grants, grant)
 		}
 	}
-	d.Set("grant", grants)
-	return nil
+
+	return grants, nil
 }
 
-func updateUser(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
+// isUserNotFound reports whether err is the error returned by statements on
+// a missing user.
+func isUserNotFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "user not found")
+}
+
+func updateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
 	name := d.Id()
 
+	// the state only holds the hash of the password, a change of the
+	// configured password shows up as a different hash.
+	if d.HasChange("password") {
+		if err := exec(ctx, conn, fmt.Sprintf("SET PASSWORD FOR %s = %s", influxql.QuoteIdent(name), influxql.QuoteString(d.Get("password").(string)))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.HasChange("admin") {
 		if !d.Get("admin").(bool) {
-			err := revokeAllOn(conn, name)
+			err := revokeAllOn(ctx, conn, name)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else {
-			err := grantAllOn(conn, name)
+			err := grantAllOn(ctx, conn, name)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	if d.HasChange("grant") {
+	// the grants read before manage_grants was turned off are not revoked
+	if d.HasChange("grant") && d.Get("manage_grants").(bool) {
 		oldGrantV, newGrantV := d.GetChange("grant")
 		oldGrant := oldGrantV.(*schema.Set).List()
 		newGrant := newGrantV.(*schema.Set).List()
@@ -222,15 +284,15 @@ func updateUser(d *schema.ResourceData, meta interface{}) error {
 			}
 
 			if !exists {
-				err := revokePrivilegeOn(conn, oldGrant["privilege"].(string), oldGrant["database"].(string), name)
+				err := revokePrivilegeOn(ctx, conn, oldGrant["privilege"].(string), oldGrant["database"].(string), name)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 			} else {
 				if privilege != oldGrant["privilege"].(string) {
-					err := grantPrivilegeOn(conn, privilege, oldGrant["database"].(string), name)
+					err := grantPrivilegeOn(ctx, conn, privilege, oldGrant["database"].(string), name)
 					if err != nil {
-						return err
+						return diag.FromErr(err)
 					}
 				}
 			}
@@ -247,32 +309,36 @@ func updateUser(d *schema.ResourceData, meta interface{}) error {
 			}
 
 			if !exists {
-				err := grantPrivilegeOn(conn, newGrant["privilege"].(string), newGrant["database"].(string), name)
+				err := grantPrivilegeOn(ctx, conn, newGrant["privilege"].(string), newGrant["database"].(string), name)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 			}
 		}
 	}
 
-	return readUser(d, meta)
-}
+	// statements were only recorded, there is nothing to read back
+	if conn.config.DryRun {
+		d.Set("admin", d.Get("admin").(bool))
+		return nil
+	}
 
-func deleteUser(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*client.Client)
-	name := d.Id()
+	return readUser(ctx, d, meta)
+}
 
-	queryStr := fmt.Sprintf("DROP USER %q", name)
-	query := client.Query{
-		Command: queryStr,
+func customizeUserDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("manage_grants").(bool) && d.Get("grant").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("grant cannot be set when manage_grants is false")
 	}
+	return nil
+}
 
-	resp, err := conn.Query(query)
-	if err != nil {
-		return err
-	}
-	if resp.Err != nil {
-		return resp.Err
+func deleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*connection)
+	name := d.Id()
+
+	if err := exec(ctx, conn, fmt.Sprintf("DROP USER %s", influxql.QuoteIdent(name))); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -1,12 +1,14 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/influxdata/influxdb/client"
 )
 
@@ -15,7 +17,7 @@ func TestAccInfluxDBUser_admin(t *testing.T) {
 
 	resourceName := "influxdb_user.test"
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccUserConfig_admin(rName),
@@ -53,7 +55,7 @@ func TestAccInfluxDBUser_grant(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccUserConfig_grant(rName),
@@ -105,7 +107,7 @@ func TestAccInfluxDBUser_revoke(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 
 	resource.Test(t, resource.TestCase{
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccUserConfig_grant(rName),
@@ -140,6 +142,148 @@ func TestAccInfluxDBUser_revoke(t *testing.T) {
 	})
 }
 
+func TestAccInfluxDBUser_grantDrift(t *testing.T) {
+	resourceName := "influxdb_user.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_revoke(rName),
+				Check:  resource.TestCheckResourceAttr(resourceName, "grant.#", "0"),
+			},
+			{
+				// a grant made outside Terraform is revoked while manage_grants is set
+				PreConfig: func() {
+					conn := testAccProvider.Meta().(*connection)
+					if err := grantPrivilegeOn(context.Background(), conn, "WRITE", rName, rName); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccUserConfig_revoke(rName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "grant.#", "0"),
+					testAccCheckUserNoGrant(rName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInfluxDBUser_password(t *testing.T) {
+	resourceName := "influxdb_user.test"
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_password(rName, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists(resourceName),
+					testAccCheckUserPassword(rName, rName, ""),
+					resource.TestCheckResourceAttr(resourceName, "password", hashSum(rName)),
+				),
+			},
+			{
+				// a grant made outside Terraform must survive the rotation
+				PreConfig: func() {
+					conn := testAccProvider.Meta().(*connection)
+					if err := grantPrivilegeOn(context.Background(), conn, "WRITE", rName, rName); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccUserConfig_password(rName, "rotated-"+rName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists(resourceName),
+					testAccCheckUserPassword(rName, "rotated-"+rName, rName),
+					testAccCheckUserGrant(rName, rName, "WRITE"),
+					resource.TestCheckResourceAttr(resourceName, "password", hashSum("rotated-"+rName)),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckUserPassword checks that the user logs in with password, and
+// no longer with oldPassword when it is set.
+func testAccCheckUserPassword(name, password, oldPassword string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccUserLogin(name, password); err != nil {
+			return fmt.Errorf("User %q does not log in with its password: %w", name, err)
+		}
+
+		if oldPassword != "" && testAccUserLogin(name, oldPassword) == nil {
+			return fmt.Errorf("User %q still logs in with its previous password", name)
+		}
+
+		return nil
+	}
+}
+
+// testAccUserLogin runs SHOW DATABASES, which any user may run, as name.
+func testAccUserLogin(name, password string) error {
+	config := testAccProvider.Meta().(*connection).config
+	config.Username = name
+	config.Password = password
+	config.SharedSecret = ""
+
+	conn, err := newConnection(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = showDatabases(context.Background(), conn)
+	return err
+}
+
+func testAccCheckUserNoGrant(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		grants, err := showGrants(context.Background(), conn, name)
+		if err != nil {
+			return err
+		}
+
+		if len(grants) > 0 {
+			return fmt.Errorf("User %q still has grants: %v", name, grants)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckUserGrant(name, database, privilege string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*connection)
+
+		grants, err := showGrants(context.Background(), conn, name)
+		if err != nil {
+			return err
+		}
+
+		for _, grant := range grants {
+			if grant["database"] == database && grant["privilege"] == privilege {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("User %q has no %s privilege on %q", name, privilege, database)
+	}
+}
+
 func testAccCheckUserExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -151,13 +295,13 @@ func testAccCheckUserExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No user id set")
 		}
 
-		conn := testAccProvider.Meta().(*client.Client)
+		conn := testAccProvider.Meta().(*connection)
 
 		query := client.Query{
 			Command: "SHOW USERS",
 		}
 
-		resp, err := conn.Query(query)
+		resp, err := conn.QueryContext(context.Background(), query)
 		if err != nil {
 			return err
 		}
@@ -187,13 +331,13 @@ func testAccCheckUserNoAdmin(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No user id set")
 		}
 
-		conn := testAccProvider.Meta().(*client.Client)
+		conn := testAccProvider.Meta().(*connection)
 
 		query := client.Query{
 			Command: "SHOW USERS",
 		}
 
-		resp, err := conn.Query(query)
+		resp, err := conn.QueryContext(context.Background(), query)
 		if err != nil {
 			return err
 		}
@@ -244,6 +388,20 @@ resource "influxdb_user" "test" {
 `, rName)
 }
 
+func testAccUserConfig_password(rName, password string) string {
+	return fmt.Sprintf(`
+resource "influxdb_database" "test" {
+  name = %[1]q
+}
+
+resource "influxdb_user" "test" {
+  name          = %[1]q
+  password      = %[2]q
+  manage_grants = false
+}
+`, rName, password)
+}
+
 func testAccUserConfig_revoke(rName string) string {
 	return fmt.Sprintf(`	
 resource "influxdb_database" "test" {
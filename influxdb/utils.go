@@ -1,10 +1,132 @@
 package influxdb
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/terraform-providers/terraform-provider-influxdb/internal/influxql"
 )
 
+// passwordLiteral matches the string literal following a PASSWORD keyword, as
+// in CREATE USER and SET PASSWORD statements.
+var passwordLiteral = regexp.MustCompile(`(?i)(PASSWORD\b[^']*)'(?:[^'\\]|\\.)*'`)
+
+// urlCredentials matches the user information of the URLs in string literals,
+// as in the DESTINATIONS of CREATE SUBSCRIPTION statements.
+var urlCredentials = regexp.MustCompile(`(?i)('[a-z][a-z0-9+.-]*://)(?:[^'@/\\]|\\.)*@`)
+
 func hashSum(contents interface{}) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(contents.(string))))
 }
+
+// redactStatement hides passwords and URL credentials from a statement before
+// it is reported.
+func redactStatement(statement string) string {
+	statement = passwordLiteral.ReplaceAllString(statement, "${1}'[REDACTED]'")
+	return urlCredentials.ReplaceAllString(statement, "${1}[REDACTED]@")
+}
+
+// uniqueId returns a random identifier, for the resources which are not
+// identified by their attributes.
+func uniqueId() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// joinId returns the parts of a resource ID separated by '/', each part path
+// escaped so that any identifier round-trips through splitId.
+func joinId(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = url.PathEscape(part)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// splitId returns the n unescaped parts of an ID built by joinId, format
+// naming the parts in the error message.
+func splitId(id string, n int, format string) ([]string, error) {
+	parts := strings.Split(id, "/")
+
+	if len(parts) != n {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected %s with '/' escaped as %%2F", id, format)
+	}
+
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("unexpected format of ID (%s), expected %s with '/' escaped as %%2F", id, format)
+		}
+
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected format of ID (%s): %s", id, err)
+		}
+		parts[i] = unescaped
+	}
+
+	return parts, nil
+}
+
+// validateDuration checks the value is a Go duration such as "1s" or "2m30s".
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration such as \"30s\": %s", k, err))
+	}
+	return
+}
+
+// validateInfluxDuration checks the value is a positive InfluxQL duration such as "1d".
+func validateInfluxDuration(v interface{}, k string) (ws []string, errors []error) {
+	duration, err := influxql.ParseDuration(v.(string))
+	if err == nil && duration <= 0 {
+		err = fmt.Errorf("duration must be positive")
+	}
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be an InfluxQL duration such as \"1d\": %s", k, err))
+	}
+	return
+}
+
+// sameInfluxDuration reports whether two InfluxQL durations are equal.
+func sameInfluxDuration(a, b string) bool {
+	da, err := influxql.ParseDuration(a)
+	if err != nil {
+		return false
+	}
+	db, err := influxql.ParseDuration(b)
+	if err != nil {
+		return false
+	}
+	return da == db
+}
+
+// influxDurationValidator checks a string attribute is an InfluxQL duration.
+type influxDurationValidator struct{}
+
+func (v influxDurationValidator) Description(ctx context.Context) string {
+	return "value must be an InfluxQL duration such as 2d, 48h0m0s or INF"
+}
+
+func (v influxDurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v influxDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := influxql.ParseDuration(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration", fmt.Sprintf("%s: %s", v.Description(ctx), err))
+	}
+}
@@ -0,0 +1,125 @@
+// Package influxql quotes identifiers and string literals, and checks
+// durations, to build InfluxQL statements out of user supplied values.
+package influxql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	identEscaper  = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`)
+)
+
+// QuoteIdent returns ident as a double quoted identifier, so that keywords and
+// any character can be used in names.
+func QuoteIdent(ident string) string {
+	return `"` + identEscaper.Replace(ident) + `"`
+}
+
+// QuoteIdents returns the dot separated quoted identifiers, such as
+// "db"."rp" for a retention policy.
+func QuoteIdents(idents ...string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = QuoteIdent(ident)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// QuoteString returns s as a single quoted string literal, as used for
+// passwords and subscription destinations.
+func QuoteString(s string) string {
+	return `'` + stringEscaper.Replace(s) + `'`
+}
+
+// QuoteRegex returns re as a regular expression literal, such as /^cpu/ to
+// match measurements. Slashes are escaped, escape sequences are left as is.
+func QuoteRegex(re string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(re); i++ {
+		switch c := re[i]; {
+		case c == '\\' && i+1 < len(re):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(re[i])
+		case c == '/':
+			b.WriteString(`\/`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('/')
+	return b.String()
+}
+
+// durationUnits maps the units of duration literals.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"u":  time.Microsecond,
+	"µ":  time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+var durationPart = regexp.MustCompile(`^(\d+)(ns|u|µ|ms|s|m|h|d|w)`)
+
+// ParseDuration parses a duration such as "2d", "1w" or "48h0m0s", as written
+// in statements and returned by SHOW statements. INF is the infinite
+// duration, reported as 0 by the server.
+func ParseDuration(s string) (time.Duration, error) {
+	if strings.EqualFold(s, "INF") {
+		return 0, nil
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration: empty")
+	}
+
+	var duration time.Duration
+	for rest := s; rest != ""; {
+		match := durationPart.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+
+		duration += time.Duration(n) * durationUnits[match[2]]
+		rest = rest[len(match[0]):]
+	}
+
+	return duration, nil
+}
+
+// Duration returns s unchanged when it is a duration literal, which is not
+// quoted in statements.
+func Duration(s string) (string, error) {
+	if _, err := ParseDuration(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// UnquoteIdent returns the name of a double quoted identifier, or ident
+// unchanged when it is not quoted.
+func UnquoteIdent(ident string) string {
+	if len(ident) < 2 || ident[0] != '"' || ident[len(ident)-1] != '"' {
+		return ident
+	}
+	return identUnescaper.Replace(ident[1 : len(ident)-1])
+}
+
+var identUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n")
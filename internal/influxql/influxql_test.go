@@ -0,0 +1,130 @@
+package influxql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	cases := []struct {
+		ident    string
+		expected string
+	}{
+		{ident: `telegraf`, expected: `"telegraf"`},
+		{ident: `my db`, expected: `"my db"`},
+		{ident: `tf-acc-test`, expected: `"tf-acc-test"`},
+		{ident: `select`, expected: `"select"`},
+		{ident: `a"b`, expected: `"a\"b"`},
+		{ident: `a\b`, expected: `"a\\b"`},
+		{ident: `a\"; DROP DATABASE "x`, expected: `"a\\\"; DROP DATABASE \"x"`},
+		{ident: "line\nbreak", expected: `"line\nbreak"`},
+		{ident: `it's`, expected: `"it's"`},
+		{ident: `über`, expected: `"über"`},
+		{ident: ``, expected: `""`},
+	}
+
+	for _, c := range cases {
+		if actual := QuoteIdent(c.ident); actual != c.expected {
+			t.Errorf("QuoteIdent(%q): expected %s, got %s", c.ident, c.expected, actual)
+		}
+	}
+}
+
+func TestUnquoteIdent(t *testing.T) {
+	for _, ident := range []string{`telegraf`, `my db`, `a"b`, `a\b`, `a\"; DROP DATABASE "x`, "line\nbreak", ``} {
+		if actual := UnquoteIdent(QuoteIdent(ident)); actual != ident {
+			t.Errorf("UnquoteIdent(QuoteIdent(%q)): got %q", ident, actual)
+		}
+	}
+
+	if actual := UnquoteIdent(`cpu`); actual != `cpu` {
+		t.Errorf("UnquoteIdent(cpu): got %q", actual)
+	}
+}
+
+func TestQuoteIdents(t *testing.T) {
+	if actual, expected := QuoteIdents("my.db", `r"p`), `"my.db"."r\"p"`; actual != expected {
+		t.Errorf("QuoteIdents: expected %s, got %s", expected, actual)
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected string
+	}{
+		{s: `super-secret`, expected: `'super-secret'`},
+		{s: `it's`, expected: `'it\'s'`},
+		{s: `back\slash`, expected: `'back\\slash'`},
+		{s: `\'`, expected: `'\\\''`},
+		{s: `x' WITH ALL PRIVILEGES --`, expected: `'x\' WITH ALL PRIVILEGES --'`},
+		{s: `"double"`, expected: `'"double"'`},
+		{s: "line\nbreak", expected: `'line\nbreak'`},
+		{s: `http://user:p@ss@kapacitor:9092`, expected: `'http://user:p@ss@kapacitor:9092'`},
+	}
+
+	for _, c := range cases {
+		if actual := QuoteString(c.s); actual != c.expected {
+			t.Errorf("QuoteString(%q): expected %s, got %s", c.s, c.expected, actual)
+		}
+	}
+}
+
+func TestQuoteRegex(t *testing.T) {
+	cases := []struct {
+		re       string
+		expected string
+	}{
+		{re: `^cpu`, expected: `/^cpu/`},
+		{re: `.*`, expected: `/.*/`},
+		{re: `^a/b$`, expected: `/^a\/b$/`},
+		{re: `^a\/b$`, expected: `/^a\/b$/`},
+		{re: `\d+\.\d+`, expected: `/\d+\.\d+/`},
+		{re: `a\\/`, expected: `/a\\\//`},
+	}
+
+	for _, c := range cases {
+		if actual := QuoteRegex(c.re); actual != c.expected {
+			t.Errorf("QuoteRegex(%q): expected %s, got %s", c.re, c.expected, actual)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		duration string
+		expected time.Duration
+	}{
+		{duration: "INF", expected: 0},
+		{duration: "inf", expected: 0},
+		{duration: "0s", expected: 0},
+		{duration: "2d", expected: 48 * time.Hour},
+		{duration: "48h0m0s", expected: 48 * time.Hour},
+		{duration: "1w", expected: 168 * time.Hour},
+		{duration: "1h30m", expected: 90 * time.Minute},
+		{duration: "100ms", expected: 100 * time.Millisecond},
+		{duration: "10u", expected: 10 * time.Microsecond},
+		{duration: "10µ", expected: 10 * time.Microsecond},
+		{duration: "5ns", expected: 5 * time.Nanosecond},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseDuration(c.duration)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): unexpected error %s", c.duration, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("ParseDuration(%q): expected %s, got %s", c.duration, c.expected, actual)
+		}
+	}
+
+	for _, duration := range []string{"", "2", "d", "1.5h", "2 d", "1y", "-1h", "1h; DROP DATABASE x"} {
+		if _, err := ParseDuration(duration); err == nil {
+			t.Errorf("ParseDuration(%q): expected an error", duration)
+		}
+		if _, err := Duration(duration); err == nil {
+			t.Errorf("Duration(%q): expected an error", duration)
+		}
+	}
+}